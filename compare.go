@@ -0,0 +1,90 @@
+package golang
+
+import "cmp"
+
+// Comparator compares a and b, returns a negative number if a < b, 0 if a == b and a positive number if a > b,
+// the same as the Comparer of Range.
+//
+// 比较函数，a < b 时返回负数，a == b 时返回 0，a > b 时返回正数，与 Range 的 Comparer 相同
+type Comparator[T any] func(a, b T) int
+
+// CompareBy returns the comparator which compares the ordered keys of the elements.
+//
+// 返回比较元素有序键的比较函数
+func CompareBy[T any, K cmp.Ordered](key func(ele T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// CompareFunc returns the comparator which compares the keys of the elements by compare.
+//
+// 返回使用 compare 比较元素键的比较函数
+func CompareFunc[T, K any](key func(ele T) K, compare func(a, b K) int) Comparator[T] {
+	return func(a, b T) int {
+		return compare(key(a), key(b))
+	}
+}
+
+// Then returns the comparator which compares by c first, and by the next comparators in order if equal,
+// e.g. golang.CompareBy(byName).Then(golang.CompareBy(byAge).Desc()).
+//
+// 返回先使用 c 比较，相等时再依次使用 next 比较的比较函数，例如 golang.CompareBy(byName).Then(golang.CompareBy(byAge).Desc())
+func (c Comparator[T]) Then(next ...Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+		for _, n := range next {
+			if r := n(a, b); r != 0 {
+				return r
+			}
+		}
+		return 0
+	}
+}
+
+// Desc returns the comparator in descending order.
+//
+// 返回降序的比较函数
+func (c Comparator[T]) Desc() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// NilFirst returns the comparator of pointers which sorts nil before the others, the others are compared
+// by c on the pointed values.
+//
+// 返回指针的比较函数，nil 排在最前，其余按指向的值使用 c 比较
+func NilFirst[T any](c func(a, b T) int) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return 1
+		}
+		return c(*a, *b)
+	}
+}
+
+// NilLast returns the comparator of pointers which sorts nil after the others, the others are compared
+// by c on the pointed values, e.g. TimeCompare.
+//
+// 返回指针的比较函数，nil 排在最后，其余按指向的值使用 c 比较，例如 TimeCompare
+func NilLast[T any](c func(a, b T) int) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		}
+		return c(*a, *b)
+	}
+}
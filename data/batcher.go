@@ -0,0 +1,299 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorBatcherClosed = errors.New("data: batcher closed")
+)
+
+const (
+	DefaultBatchSize     = 100         // 默认批量大小
+	DefaultBatchInterval = time.Second // 默认批量间隔
+)
+
+type batcherOptions struct {
+	size     int
+	interval time.Duration
+	pending  int
+	onError  func(err error)
+}
+
+// BatcherOption is the option of Batcher.
+//
+// Batcher 选项
+type BatcherOption func(o *batcherOptions)
+
+// BatchSize sets the number of items which triggers a flush, default is DefaultBatchSize.
+//
+// 设置触发批量写入的数据项数量，默认为 DefaultBatchSize
+func BatchSize(size int) BatcherOption {
+	return func(o *batcherOptions) {
+		o.size = size
+	}
+}
+
+// BatchInterval sets the maximum time an item waits in the buffer, default is DefaultBatchInterval,
+// less than or equal to 0 disables the time trigger.
+//
+// 设置数据项在缓冲中等待的最长时间，默认为 DefaultBatchInterval，小于等于 0 表示不按时间触发
+func BatchInterval(interval time.Duration) BatcherOption {
+	return func(o *batcherOptions) {
+		o.interval = interval
+	}
+}
+
+// BatchPending sets the number of batches waiting for flush, Add blocks when the pending batches are
+// full, i.e. the flushes fall behind, default is 1.
+//
+// 设置等待写入的批次数量，等待的批次已满（即写入跟不上）时 Add 将阻塞，默认为 1
+func BatchPending(pending int) BatcherOption {
+	return func(o *batcherOptions) {
+		o.pending = pending
+	}
+}
+
+// OnBatchError sets the handler of the flush errors, called in the flushing goroutine.
+//
+// 设置批量写入错误的处理函数，在写入协程中调用
+func OnBatchError(f func(err error)) BatcherOption {
+	return func(o *batcherOptions) {
+		o.onError = f
+	}
+}
+
+type batch[T any] struct {
+	items []T
+	done  chan struct{} // 非 nil 时写入完成后关闭，用于 Flush 与 Close 等待
+}
+
+// Batcher groups the streaming items and calls the flush function with every N items or every T
+// interval, whichever comes first. The batches are flushed one by one in order by a goroutine, Add
+// blocks when the flushes fall behind. Batcher is safe for concurrent use.
+//
+// 批量处理器，将流式数据项按每 N 项或每隔 T 时间（先到者为准）分组调用写入函数。各批次由一个协程按顺序逐个写入，
+// 写入跟不上时 Add 将阻塞。Batcher 可以安全地并发使用。
+type Batcher[T any] struct {
+	options    *batcherOptions
+	flush      func(ctx context.Context, items []T) error
+	queue      chan *batch[T]
+	stopped    chan struct{}
+	lock       chan struct{} // 缓冲的锁，可随 ctx 放弃等待
+	buffer     []T
+	timer      *time.Timer
+	generation int
+	closed     bool
+	errMutex   sync.Mutex
+	errs       []error
+}
+
+// NewBatcher creates the batcher and starts the flushing goroutine, ctx is passed to flush, call Close
+// to flush the remaining items and stop the goroutine.
+//
+// 创建批量处理器并启动写入协程，ctx 传递给 flush，调用 Close 写入剩余的数据项并结束协程
+func NewBatcher[T any](ctx context.Context, flush func(ctx context.Context, items []T) error, opts ...BatcherOption) *Batcher[T] {
+	o := &batcherOptions{size: DefaultBatchSize, interval: DefaultBatchInterval, pending: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.size = max(o.size, 1)
+	o.pending = max(o.pending, 0)
+	b := &Batcher[T]{
+		options: o,
+		flush:   flush,
+		queue:   make(chan *batch[T], o.pending),
+		lock:    make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+	go b.run(ctx)
+	return b
+}
+
+func (b *Batcher[T]) run(ctx context.Context) {
+	defer close(b.stopped)
+	for x := range b.queue {
+		if len(x.items) > 0 {
+			if err := b.flush(ctx, x.items); err != nil {
+				b.errMutex.Lock()
+				b.errs = append(b.errs, err)
+				b.errMutex.Unlock()
+				if b.options.onError != nil {
+					b.options.onError(err)
+				}
+			}
+		}
+		if x.done != nil {
+			close(x.done)
+		}
+	}
+}
+
+// errors returns and clears the errors occurred.
+func (b *Batcher[T]) errors() error {
+	b.errMutex.Lock()
+	defer b.errMutex.Unlock()
+
+	err := errors.Join(b.errs...)
+	b.errs = nil
+	return err
+}
+
+// acquire acquires the lock of the buffer, returns the error of ctx if ctx is done before, so that the
+// callers waiting for a blocked Add give up with their own ctx.
+func (b *Batcher[T]) acquire(ctx context.Context) error {
+	select {
+	case b.lock <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case b.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release releases the lock of the buffer.
+func (b *Batcher[T]) release() {
+	<-b.lock
+}
+
+// send sends the first n items of the buffer, must be called with the lock held.
+func (b *Batcher[T]) send(ctx context.Context, n int, done chan struct{}) error {
+	select {
+	case b.queue <- b.take(n, done):
+	case <-ctx.Done():
+		// 未发送的数据项保留在缓冲中
+		return ctx.Err()
+	}
+	b.sent(n)
+	return nil
+}
+
+// take copies the first n items of the buffer as a batch, must be called with the lock held.
+func (b *Batcher[T]) take(n int, done chan struct{}) *batch[T] {
+	x := &batch[T]{items: make([]T, n), done: done}
+	copy(x.items, b.buffer)
+	return x
+}
+
+// sent removes the first n items sent from the buffer, must be called with the lock held.
+func (b *Batcher[T]) sent(n int) {
+	b.buffer = b.buffer[n:]
+	if len(b.buffer) == 0 {
+		b.buffer = nil
+	}
+	b.schedule()
+}
+
+// schedule restarts the timer of the buffered items, must be called with the lock held.
+func (b *Batcher[T]) schedule() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.generation++
+	if len(b.buffer) == 0 || b.options.interval <= 0 || b.closed {
+		return
+	}
+	g := b.generation
+	b.timer = time.AfterFunc(b.options.interval, func() {
+		b.lock <- struct{}{}
+		defer b.release()
+
+		if g != b.generation {
+			return
+		}
+		b.timer = nil
+		if b.closed || len(b.buffer) == 0 {
+			return
+		}
+		n := len(b.buffer)
+		select {
+		case b.queue <- b.take(n, nil):
+			b.sent(n)
+		default:
+			// 等待的批次已满，不在持有锁时阻塞，稍后重试
+			b.schedule()
+		}
+	})
+}
+
+// Add adds the items, flushes the full batches, blocks until the batches are accepted when the flushes
+// fall behind or ctx is done, the items not accepted remain in the buffer and are flushed by the interval.
+// Waiting for another blocked Add or Flush also ends when ctx is done.
+//
+// 添加数据项并写入已满的批次，写入跟不上时阻塞直到批次被接收或 ctx 结束，未被接收的数据项保留在缓冲中并按时间间隔写入。
+// 等待其它阻塞的 Add 或 Flush 时同样在 ctx 结束时返回
+func (b *Batcher[T]) Add(ctx context.Context, items ...T) (err error) {
+	if err = b.acquire(ctx); err != nil {
+		return
+	}
+	defer b.release()
+
+	if b.closed {
+		return ErrorBatcherClosed
+	}
+	b.buffer = append(b.buffer, items...)
+	for len(b.buffer) >= b.options.size {
+		if err = b.send(ctx, b.options.size, nil); err != nil {
+			break
+		}
+	}
+	if b.timer == nil && len(b.buffer) > 0 && b.options.interval > 0 {
+		// 缓冲中的数据项（包括未被接收而保留的）没有计时，开始计时
+		b.schedule()
+	}
+	return
+}
+
+// Flush flushes the buffered items and waits until all the accepted batches are flushed, returns the
+// errors of the flushes since the last Flush or Close.
+//
+// 写入缓冲中的数据项并等待所有已接收的批次写入完成，返回自上次 Flush 或 Close 以来的写入错误
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	if err := b.acquire(ctx); err != nil {
+		return err
+	}
+	if b.closed {
+		b.release()
+		return ErrorBatcherClosed
+	}
+	done := make(chan struct{})
+	err := b.send(ctx, len(b.buffer), done)
+	b.release()
+	if err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return b.errors()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes the remaining items, waits until all batches are flushed and stops the flushing
+// goroutine, returns the errors of the flushes since the last Flush. Add and Flush return
+// ErrorBatcherClosed after Close.
+//
+// 写入剩余的数据项，等待所有批次写入完成并结束写入协程，返回自上次 Flush 以来的写入错误。
+// Close 之后 Add 与 Flush 返回 ErrorBatcherClosed
+func (b *Batcher[T]) Close() error {
+	b.lock <- struct{}{}
+	if b.closed {
+		b.release()
+		return ErrorBatcherClosed
+	}
+	b.closed = true
+	_ = b.send(context.Background(), len(b.buffer), nil)
+	close(b.queue)
+	b.release()
+	<-b.stopped
+	return b.errors()
+}
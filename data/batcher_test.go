@@ -0,0 +1,200 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatcherInterval(t *testing.T) {
+	flushed := make(chan []int, 1)
+	b := NewBatcher(context.Background(), func(ctx context.Context, items []int) error {
+		flushed <- items
+		return nil
+	}, BatchSize(10), BatchInterval(10*time.Millisecond))
+	defer b.Close()
+
+	if err := b.Add(context.Background(), 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case items := <-flushed:
+		if len(items) != 2 {
+			t.Errorf("want 2 items, got %v", items)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batch not flushed by the interval")
+	}
+}
+
+func TestBatcherBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	b := NewBatcher(context.Background(), func(ctx context.Context, items []int) error {
+		<-release
+		return nil
+	}, BatchSize(1), BatchInterval(0), BatchPending(1))
+
+	ctx := context.Background()
+	// 第一批正在写入，第二批等待写入
+	_ = b.Add(ctx, 1)
+	_ = b.Add(ctx, 2)
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := b.Add(timeout, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	close(release)
+	if err := b.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := b.Add(ctx, 4); !errors.Is(err, ErrorBatcherClosed) {
+		t.Errorf("want %v, got %v", ErrorBatcherClosed, err)
+	}
+}
+
+func TestBatcherIntervalBackpressure(t *testing.T) {
+	var total atomic.Int64
+	b := NewBatcher(context.Background(), func(ctx context.Context, items []int) error {
+		time.Sleep(300 * time.Millisecond)
+		total.Add(int64(len(items)))
+		return nil
+	}, BatchSize(100), BatchInterval(5*time.Millisecond), BatchPending(0))
+
+	ctx := context.Background()
+	// 第一批由计时器发送并正在写入，第二批的计时器无法发送
+	_ = b.Add(ctx, 1)
+	time.Sleep(20 * time.Millisecond)
+	_ = b.Add(ctx, 2)
+	time.Sleep(20 * time.Millisecond)
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := b.Add(timeout, 3); err != nil {
+		t.Error(err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("Add blocked by the timer for %v", d)
+	}
+	if err := b.Close(); err != nil {
+		t.Error(err)
+	}
+	if n := total.Load(); n != 3 {
+		t.Errorf("want 3 items flushed, got %d", n)
+	}
+}
+
+func TestBatcherLeftover(t *testing.T) {
+	release := make(chan struct{})
+	flushed := make(chan []int, 4)
+	b := NewBatcher(context.Background(), func(ctx context.Context, items []int) error {
+		<-release
+		flushed <- items
+		return nil
+	}, BatchSize(2), BatchInterval(10*time.Millisecond), BatchPending(0))
+	defer b.Close()
+
+	ctx := context.Background()
+	// 第一批正在写入，第二批未被接收而保留在缓冲中
+	_ = b.Add(ctx, 1, 2)
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := b.Add(timeout, 3, 4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	close(release)
+	<-flushed
+	select {
+	case items := <-flushed:
+		if len(items) != 2 || items[0] != 3 {
+			t.Errorf("want [3 4], got %v", items)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("leftover items not flushed by the interval")
+	}
+}
+
+func TestBatcherWaitCancel(t *testing.T) {
+	release := make(chan struct{})
+	b := NewBatcher(context.Background(), func(ctx context.Context, items []int) error {
+		<-release
+		return nil
+	}, BatchSize(1), BatchInterval(0), BatchPending(0))
+
+	ctx := context.Background()
+	// 第一批正在写入，第二个 Add 阻塞
+	_ = b.Add(ctx, 1)
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- b.Add(ctx, 2)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// 等待阻塞的 Add 时同样响应各自的 ctx
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := b.Add(timeout, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Add: want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if err := b.Flush(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush: want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if d := time.Since(start); d > 200*time.Millisecond {
+		t.Errorf("blocked for %v", d)
+	}
+	close(release)
+	if err := <-blocked; err != nil {
+		t.Error(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBatcherConcurrent(t *testing.T) {
+	var (
+		total   atomic.Int64
+		batches atomic.Int64
+		reports atomic.Int64
+		failed  = errors.New("failed")
+	)
+	b := NewBatcher(context.Background(), func(ctx context.Context, items []int) error {
+		if len(items) > 7 {
+			t.Errorf("batch too large: %d", len(items))
+		}
+		total.Add(int64(len(items)))
+		if batches.Add(1) == 1 {
+			return failed
+		}
+		return nil
+	}, BatchSize(7), BatchInterval(time.Millisecond), BatchPending(2), OnBatchError(func(err error) {
+		reports.Add(1)
+	}))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if err := b.Add(context.Background(), i); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := b.Close(); !errors.Is(err, failed) {
+		t.Errorf("want %v, got %v", failed, err)
+	}
+	if n := total.Load(); n != 800 {
+		t.Errorf("want 800 items, got %d", n)
+	}
+	if n := reports.Load(); n != 1 {
+		t.Errorf("want 1 error reported, got %d", n)
+	}
+}
@@ -0,0 +1,323 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorJobInvalid = errors.New("data: invalid job name")
+)
+
+// Checkpoint is the progress of a chunk job.
+//
+// 分块任务的进度
+type Checkpoint struct {
+	Index   int       `json:"index"`            // 最后完成的块序号，0 开始，-1 表示尚未完成任何块
+	Cursor  string    `json:"cursor,omitempty"` // 最后完成的游标，用于键集分页
+	Updated time.Time `json:"updated"`          // 更新时间
+}
+
+// CheckpointStore stores the checkpoints of the chunk jobs by job name.
+//
+// 按任务名称保存分块任务的进度
+type CheckpointStore interface {
+	// Load returns the checkpoint of the job, nil if none.
+	//
+	// Load 返回任务的进度，没有则返回 nil
+	Load(ctx context.Context, job string) (*Checkpoint, error)
+	// Save saves the checkpoint of the job.
+	//
+	// Save 保存任务的进度
+	Save(ctx context.Context, job string, cp *Checkpoint) error
+	// Clear removes the checkpoint of the job.
+	//
+	// Clear 删除任务的进度
+	Clear(ctx context.Context, job string) error
+}
+
+type resumeOptions struct {
+	attempts int
+	backoff  time.Duration
+	maximum  time.Duration
+	keep     bool
+}
+
+// ResumeOption is the option of the resumable chunk jobs.
+//
+// 可恢复分块任务选项
+type ResumeOption func(o *resumeOptions)
+
+// Retry retries the failed chunk up to attempts times in total, waits backoff before the first retry
+// and doubles it for every retry up to maximum (less than or equal to 0 no maximum).
+//
+// 失败的块最多共尝试 attempts 次，第一次重试前等待 backoff，之后每次重试等待时间加倍，最长为 maximum（小于等于 0 表示不限制）
+func Retry(attempts int, backoff, maximum time.Duration) ResumeOption {
+	return func(o *resumeOptions) {
+		o.attempts = attempts
+		o.backoff = backoff
+		o.maximum = maximum
+	}
+}
+
+// KeepCheckpoint keeps the checkpoint after the job is completed, otherwise it is cleared and the job
+// starts from the beginning next time.
+//
+// 任务完成后保留进度，否则清除进度，下次从头开始
+func KeepCheckpoint() ResumeOption {
+	return func(o *resumeOptions) {
+		o.keep = true
+	}
+}
+
+// resume calls step from the stored checkpoint until it returns false or error, step updates the
+// checkpoint only if the chunk is completed, the checkpoint is saved after every completed chunk.
+func resume(ctx context.Context, store CheckpointStore, job string, opts []ResumeOption,
+	step func(ctx context.Context, cp *Checkpoint) (next bool, err error)) (count int, err error) {
+	o := &resumeOptions{attempts: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	cp, err := store.Load(ctx, job)
+	if err != nil {
+		return
+	}
+	if cp == nil {
+		cp = &Checkpoint{Index: -1}
+	}
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		count++
+		var next bool
+		backoff := o.backoff
+		for attempt := 1; ; attempt++ {
+			if next, err = step(ctx, cp); err == nil || attempt >= o.attempts {
+				break
+			}
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return count, errors.Join(err, ctx.Err())
+			}
+			backoff *= 2
+			if o.maximum > 0 && backoff > o.maximum {
+				backoff = o.maximum
+			}
+		}
+		if err != nil {
+			return
+		}
+		cp.Updated = time.Now()
+		if !next {
+			break
+		}
+		if err = store.Save(ctx, job, cp); err != nil {
+			return
+		}
+	}
+	if o.keep {
+		err = store.Save(ctx, job, cp)
+	} else {
+		err = store.Clear(ctx, job)
+	}
+	return
+}
+
+// ResumeChunk is the resumable variant of Chunk, starts from the chunk after the checkpoint of job stored
+// in store, and saves the checkpoint after every completed chunk, count is the number of chunks of this run.
+//
+// Chunk 的可恢复版本，从 store 中保存的 job 进度的下一块开始执行，每完成一块保存一次进度，count 为本次执行的块数
+func ResumeChunk(ctx context.Context, store CheckpointStore, job string, size int, f func(ctx context.Context, start, end int) (next bool, err error),
+	opts ...ResumeOption) (count int, err error) {
+	if size < 1 {
+		// 不执行
+		return
+	}
+	return resume(ctx, store, job, opts, func(ctx context.Context, cp *Checkpoint) (next bool, err error) {
+		i := cp.Index + 1
+		if next, err = f(ctx, i*size, (i+1)*size-1); err == nil {
+			cp.Index = i
+		}
+		return
+	})
+}
+
+// ResumeOffset is the resumable variant of Offset, see ResumeChunk.
+//
+// Offset 的可恢复版本，参见 ResumeChunk
+func ResumeOffset(ctx context.Context, store CheckpointStore, job string, limit int, f func(ctx context.Context, offset int) (next bool, err error),
+	opts ...ResumeOption) (count int, err error) {
+	if limit < 1 {
+		// 不执行
+		return
+	}
+	return resume(ctx, store, job, opts, func(ctx context.Context, cp *Checkpoint) (next bool, err error) {
+		i := cp.Index + 1
+		if next, err = f(ctx, i*limit); err == nil {
+			cp.Index = i
+		}
+		return
+	})
+}
+
+// ResumePaged is the resumable variant of Paged, see ResumeChunk.
+//
+// Paged 的可恢复版本，参见 ResumeChunk
+func ResumePaged(ctx context.Context, store CheckpointStore, job string, capacity int, f func(ctx context.Context, number, start, end int) (next bool, err error),
+	opts ...ResumeOption) (count int, err error) {
+	if capacity < 1 {
+		// 不执行
+		return
+	}
+	return resume(ctx, store, job, opts, func(ctx context.Context, cp *Checkpoint) (next bool, err error) {
+		i := cp.Index + 1
+		if next, err = f(ctx, i+1, i*capacity, (i+1)*capacity-1); err == nil {
+			cp.Index = i
+		}
+		return
+	})
+}
+
+// ResumeKeyset is the resumable variant of Keyset, the last seen key is stored as the JSON cursor of the
+// checkpoint, f returns nil for an empty page to keep the previous cursor, see Keyset and ResumeChunk.
+//
+// Keyset 的可恢复版本，最后一个键以 JSON 形式保存为进度的游标，f 对空页返回 nil 以保留之前的游标，参见 Keyset 和 ResumeChunk
+func ResumeKeyset[K any](ctx context.Context, store CheckpointStore, job string, f func(ctx context.Context, after *K) (last *K, next bool, err error),
+	opts ...ResumeOption) (count int, err error) {
+	return resume(ctx, store, job, opts, func(ctx context.Context, cp *Checkpoint) (next bool, err error) {
+		var after *K
+		if cp.Cursor != "" {
+			after = new(K)
+			if err = json.Unmarshal([]byte(cp.Cursor), after); err != nil {
+				return false, fmt.Errorf("%w: %w", ErrorCursorInvalid, err)
+			}
+		}
+		var k *K
+		if k, next, err = f(ctx, after); err != nil {
+			return
+		}
+		if k != nil {
+			var b []byte
+			if b, err = json.Marshal(*k); err != nil {
+				return
+			}
+			cp.Cursor = string(b)
+		}
+		cp.Index++
+		return
+	})
+}
+
+var jobName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// FileCheckpointStore is a CheckpointStore that saves the checkpoint of each job in the JSON file
+// "<job>.json" of the directory, the file is replaced atomically.
+//
+// 基于文件的进度存储，每个任务的进度保存在目录下的 "<job>.json" 文件中，文件以原子方式替换
+type FileCheckpointStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileCheckpointStore creates the file checkpoint store in the directory, which is created if not exists.
+//
+// 在目录 dir 中创建基于文件的进度存储，目录不存在时自动创建
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+func (s *FileCheckpointStore) path(job string) (string, error) {
+	if !jobName.MatchString(job) || job == "." || job == ".." {
+		return "", fmt.Errorf("%w: %q", ErrorJobInvalid, job)
+	}
+	return filepath.Join(s.dir, job+".json"), nil
+}
+
+// Load returns the checkpoint of the job, nil if none.
+//
+// 返回任务的进度，没有则返回 nil
+func (s *FileCheckpointStore) Load(ctx context.Context, job string) (*Checkpoint, error) {
+	name, err := s.path(job)
+	if err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	b, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{}
+	if err = json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("data: checkpoint %s: %w", name, err)
+	}
+	return cp, nil
+}
+
+// Save saves the checkpoint of the job, the file is written to a temporary file, synchronized to the
+// disk and renamed.
+//
+// 保存任务的进度，先写入临时文件并同步到磁盘，再重命名
+func (s *FileCheckpointStore) Save(ctx context.Context, job string, cp *Checkpoint) error {
+	name, err := s.path(job)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f, err := os.CreateTemp(s.dir, job+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if _, err = f.Write(b); err == nil {
+		err = f.Sync()
+	}
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// Clear removes the checkpoint of the job.
+//
+// 删除任务的进度
+func (s *FileCheckpointStore) Clear(ctx context.Context, job string) error {
+	name, err := s.path(job)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err = os.Remove(name); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestResumeChunk(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	failed := errors.New("failed")
+	var starts []int
+	f := func(fail int) func(ctx context.Context, start, end int) (bool, error) {
+		return func(ctx context.Context, start, end int) (bool, error) {
+			if start == fail {
+				return false, failed
+			}
+			starts = append(starts, start)
+			return end < 9, nil
+		}
+	}
+	// 第一次执行在第三块失败，进度停留在第二块
+	if _, err = ResumeChunk(ctx, store, "job", 3, f(6)); !errors.Is(err, failed) {
+		t.Fatalf("want %v, got %v", failed, err)
+	}
+	cp, err := store.Load(ctx, "job")
+	if err != nil || cp == nil || cp.Index != 1 {
+		t.Fatalf("want checkpoint index 1, got %+v, %v", cp, err)
+	}
+	// 重启后从第三块继续
+	starts = nil
+	c, err := ResumeChunk(ctx, store, "job", 3, f(-1))
+	if err != nil || c != 2 || len(starts) != 2 || starts[0] != 6 || starts[1] != 9 {
+		t.Fatalf("want resumed from 6, got %v, %d, %v", starts, c, err)
+	}
+	// 完成后清除进度
+	if cp, err = store.Load(ctx, "job"); err != nil || cp != nil {
+		t.Fatalf("want no checkpoint, got %+v, %v", cp, err)
+	}
+	if _, err = store.Load(ctx, "../job"); !errors.Is(err, ErrorJobInvalid) {
+		t.Errorf("want %v, got %v", ErrorJobInvalid, err)
+	}
+}
+
+func TestResumeRetry(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	attempts := 0
+	c, err := ResumeOffset(ctx, store, "retry", 10, func(ctx context.Context, offset int) (bool, error) {
+		if offset == 10 {
+			attempts++
+			if attempts < 3 {
+				return false, errors.New("transient")
+			}
+		}
+		return offset < 20, nil
+	}, Retry(3, time.Millisecond, 2*time.Millisecond), KeepCheckpoint())
+	if err != nil || c != 3 || attempts != 3 {
+		t.Fatalf("want 3 chunks and 3 attempts, got %d, %d, %v", c, attempts, err)
+	}
+	cp, err := store.Load(ctx, "retry")
+	if err != nil || cp == nil || cp.Index != 2 {
+		t.Fatalf("want kept checkpoint index 2, got %+v, %v", cp, err)
+	}
+}
+
+func TestResumeKeyset(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	keys := []int{1, 2, 3, 4, 5}
+	page := func(after *int) []int {
+		i := 0
+		if after != nil {
+			for i < len(keys) && keys[i] <= *after {
+				i++
+			}
+		}
+		return keys[i:min(i+2, len(keys))]
+	}
+	failed := errors.New("failed")
+	_, err = ResumeKeyset(ctx, store, "keyset", func(ctx context.Context, after *int) (*int, bool, error) {
+		if after != nil && *after == 2 {
+			return nil, false, failed
+		}
+		p := page(after)
+		return &p[len(p)-1], true, nil
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("want %v, got %v", failed, err)
+	}
+	var seen []int
+	_, err = ResumeKeyset(ctx, store, "keyset", func(ctx context.Context, after *int) (*int, bool, error) {
+		p := page(after)
+		if len(p) == 0 {
+			return nil, false, nil
+		}
+		seen = append(seen, p...)
+		return &p[len(p)-1], true, nil
+	}, KeepCheckpoint())
+	if err != nil || len(seen) != 3 || seen[0] != 3 {
+		t.Fatalf("want resumed after key 2, got %v, %v", seen, err)
+	}
+	cp, _ := store.Load(ctx, "keyset")
+	if cp == nil || cp.Cursor != "5" {
+		t.Errorf("want cursor 5 kept, got %+v", cp)
+	}
+}
+
+func TestResumeKeysetZeroKey(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	// 最后一个键恰为零值，依然作为游标保存
+	keys := []int{-2, -1, 0}
+	_, err = ResumeKeyset(ctx, store, "zero", func(ctx context.Context, after *int) (*int, bool, error) {
+		i := 0
+		if after != nil {
+			i = slices.Index(keys, *after) + 1
+		}
+		return &keys[i], i+1 < len(keys), nil
+	}, KeepCheckpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp, _ := store.Load(ctx, "zero")
+	if cp == nil || cp.Cursor != "0" || cp.Index != 2 {
+		t.Errorf("want cursor 0 after 3 pages, got %+v", cp)
+	}
+}
@@ -0,0 +1,29 @@
+package data_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keepitlight/golang/data"
+)
+
+func ExampleBatcher() {
+	ctx := context.Background()
+	b := data.NewBatcher(ctx, func(ctx context.Context, items []int) error {
+		// 例如 INSERT INTO events VALUES (...), (...), ...
+		fmt.Println(items)
+		return nil
+	}, data.BatchSize(3), data.BatchInterval(0))
+	for i := 1; i <= 7; i++ {
+		_ = b.Add(ctx, i)
+	}
+	_ = b.Flush(ctx)
+	_ = b.Add(ctx, 8, 9)
+	fmt.Println(b.Close())
+	// Output:
+	// [1 2 3]
+	// [4 5 6]
+	// [7]
+	// [8 9]
+	// <nil>
+}
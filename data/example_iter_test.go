@@ -0,0 +1,81 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/keepitlight/golang/data"
+)
+
+func ExampleChunks() {
+	for start, end := range data.Chunks(3, 10) {
+		fmt.Println(start, end)
+	}
+	// Output:
+	// 0 2
+	// 3 5
+	// 6 8
+	// 9 9
+}
+
+func ExampleOffsets() {
+	for offset := range data.Offsets(3, -1) {
+		if offset > 6 {
+			// 总数未知时为无限序列，需跳出循环
+			break
+		}
+		fmt.Println(offset)
+	}
+	// Output:
+	// 0
+	// 3
+	// 6
+}
+
+func ExamplePages() {
+	for page := range data.Pages(4, 10) {
+		fmt.Println(page.Number, page.Start, page.End)
+	}
+	// Output:
+	// 1 0 3
+	// 2 4 7
+	// 3 8 9
+}
+
+func ExampleFetch() {
+	records := []string{"a", "b", "c", "d", "e"}
+	load := func(ctx context.Context, page *data.Bounds) ([]string, error) {
+		fmt.Println("load", page.Number)
+		if page.Start >= len(records) {
+			return nil, nil
+		}
+		return records[page.Start:min(page.End+1, len(records))], nil
+	}
+	for item, err := range data.Fetch(context.Background(), 2, load) {
+		fmt.Println(item, err)
+	}
+
+	failed := errors.New("failed")
+	for item, err := range data.Fetch(context.Background(), 2, func(ctx context.Context, page *data.Bounds) ([]string, error) {
+		if page.Number > 1 {
+			return nil, failed
+		}
+		return records[:2], nil
+	}) {
+		fmt.Println(item, err)
+	}
+	// Output:
+	// load 1
+	// a <nil>
+	// b <nil>
+	// load 2
+	// c <nil>
+	// d <nil>
+	// load 3
+	// e <nil>
+	// load 4
+	// a <nil>
+	// b <nil>
+	//  failed
+}
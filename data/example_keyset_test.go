@@ -0,0 +1,85 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keepitlight/golang/data"
+	"github.com/keepitlight/golang/tuples"
+)
+
+func ExampleKeyset() {
+	type Key = *tuples.Pair[string, int]
+	// records sorted by (group, id), e.g. "SELECT ... WHERE (group, id) > (?, ?) ORDER BY group, id LIMIT 2"
+	records := []Key{
+		{A: "a", B: 1}, {A: "a", B: 3}, {A: "b", B: 2}, {A: "b", B: 5}, {A: "c", B: 4},
+	}
+	after := func(k Key) []Key {
+		if k == nil {
+			return records
+		}
+		for i, r := range records {
+			if r.A > k.A || r.A == k.A && r.B > k.B {
+				return records[i:]
+			}
+		}
+		return nil
+	}
+	c, last, e := data.Keyset[Key](nil, func(k *Key) (last *Key, next bool, err error) {
+		var page []Key
+		if k == nil {
+			page = after(nil)
+		} else {
+			page = after(*k)
+		}
+		page = page[:min(2, len(page))]
+		for i, r := range page {
+			if i > 0 {
+				fmt.Print(" ")
+			}
+			fmt.Print(r.A, r.B)
+		}
+		fmt.Println()
+		if len(page) == 0 {
+			// 空页，保留之前的键
+			return nil, false, nil
+		}
+		// 满页才可能有下一页
+		return &page[len(page)-1], len(page) == 2, nil
+	})
+	fmt.Println(c, (*last).A, (*last).B, e)
+	// Output:
+	// a1 a3
+	// b2 b5
+	// c4
+	// 3 c 4 <nil>
+}
+
+func ExampleCursorCodec() {
+	codec, err := data.NewCursorCodec[*tuples.Pair[string, int]]([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		panic(err)
+	}
+	token, _ := codec.Encode(&data.Cursor[*tuples.Pair[string, int]]{
+		Key:   &tuples.Pair[string, int]{A: "b", B: 5},
+		Order: data.Descending,
+	})
+	cursor, err := codec.Decode(token)
+	fmt.Println(cursor.Key.A, cursor.Key.B, cursor.Order.Operator(), cursor.Order.Direction(), err)
+
+	// 篡改的令牌无法解码
+	_, err = codec.Decode("x" + token)
+	fmt.Println(errors.Is(err, data.ErrorCursorInvalid))
+	other, _ := data.NewCursorCodec[*tuples.Pair[string, int]]([]byte("fedcba9876543210fedcba9876543210"))
+	_, err = other.Decode(token)
+	fmt.Println(errors.Is(err, data.ErrorCursorInvalid))
+
+	// 密钥过短
+	_, err = data.NewCursorCodec[*tuples.Pair[string, int]]([]byte("secret"))
+	fmt.Println(errors.Is(err, data.ErrorSecretInvalid))
+	// Output:
+	// b 5 < DESC <nil>
+	// true
+	// true
+	// true
+}
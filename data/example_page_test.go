@@ -0,0 +1,47 @@
+package data_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/keepitlight/golang/data"
+)
+
+func ExamplePage() {
+	p, err := data.ParsePage("2", "", 45, 20, 100)
+	fmt.Println(err)
+	fmt.Println(p.Offset(), p.Limit(), p.Pages(), p.Prev(), p.Next(), p.HasNext())
+	fmt.Println(p.Bounds().Start, p.Bounds().End)
+
+	base, _ := url.Parse("https://example.com/users?sort=name")
+	fmt.Println(p.Link(base, "", ""))
+
+	b, _ := json.Marshal(data.NewEnvelope([]string{"a", "b"}, p))
+	fmt.Println(string(b))
+	// Output:
+	// <nil>
+	// 20 20 3 1 3 true
+	// 20 39
+	// <https://example.com/users?page=1&size=20&sort=name>; rel="first", <https://example.com/users?page=1&size=20&sort=name>; rel="prev", <https://example.com/users?page=3&size=20&sort=name>; rel="next", <https://example.com/users?page=3&size=20&sort=name>; rel="last"
+	// {"items":["a","b"],"page":{"number":2,"size":20,"total":45,"pages":3,"prev":1,"next":3}}
+}
+
+func ExamplePage_Clamp() {
+	p, err := data.ParsePage("9", "500", 45, 20, 100)
+	fmt.Println(errors.Is(err, data.ErrorPageOutOfRange))
+	p = p.Clamp()
+	fmt.Println(p.Number, p.Size, p.Bounds().Start, p.Bounds().End)
+
+	// 格式错误的页号替换为默认值 1
+	p, err = data.ParsePage("x", "", 45, 20, 100)
+	fmt.Println(errors.Is(err, data.ErrorPageInvalid), p.Number, p.Size)
+	_, err = data.NewPage(0, 20, 45)
+	fmt.Println(errors.Is(err, data.ErrorPageInvalid))
+	// Output:
+	// true
+	// 1 100 0 44
+	// true 1 20
+	// true
+}
@@ -0,0 +1,46 @@
+package data_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keepitlight/golang/data"
+)
+
+func ExampleParallelChunk() {
+	var sum int
+	c, e := data.ParallelChunk(context.Background(), 3, 4, 14, func(ctx context.Context, start, end int) (v int, next bool, err error) {
+		// 计算 [start, end] 的和，total 已知时 end 不超过最后一条记录
+		for i := start; i <= end; i++ {
+			v += i
+		}
+		return v, true, nil
+	}, func(index int, v int) {
+		fmt.Printf("chunk %d: %d\n", index, v)
+		sum += v
+	}, data.Ordered())
+	fmt.Println(c, e, sum)
+	// Output:
+	// chunk 0: 3
+	// chunk 1: 12
+	// chunk 2: 21
+	// chunk 3: 30
+	// chunk 4: 25
+	// 5 <nil> 91
+}
+
+func ExampleParallelPaged() {
+	// 数据总量未知，第 4 页之后没有更多数据
+	c, e := data.ParallelPaged(context.Background(), 3, 4, -1, func(ctx context.Context, number, start, end int) (v string, next bool, err error) {
+		return fmt.Sprintf("p%d: [%d, %d]", number, start, end), number < 4, nil
+	}, func(index int, v string) {
+		fmt.Println(v)
+	})
+	fmt.Println(c, e)
+	// Output:
+	// p1: [0, 2]
+	// p2: [3, 5]
+	// p3: [6, 8]
+	// p4: [9, 11]
+	// 4 <nil>
+}
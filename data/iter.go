@@ -0,0 +1,103 @@
+package data
+
+import (
+	"context"
+	"iter"
+)
+
+// Bounds is the descriptor of a page.
+//
+// 分页描述
+type Bounds struct {
+	Number int `json:"number"` // 1 开始的页号
+	Start  int `json:"start"`  // 0 开始的数据记录起始位置
+	End    int `json:"end"`    // 0 开始的数据记录结束位置，包含
+}
+
+// Chunks returns the sequence of start and end positions of the chunks, size is the chunk size >= 1,
+// total is the number of data records, less than 0 if unknown (infinite sequence, break the loop to stop).
+//
+// 返回各块起止位置的序列，size 为分块大小，total 为数据记录总数，小于 0 表示未知（无限序列，需跳出循环以结束）
+func Chunks(size, total int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		if size < 1 {
+			// 不执行
+			return
+		}
+		for i, n := 0, chunks(size, total); n < 0 || i < n; i++ {
+			if !yield(i*size, last(total, (i+1)*size-1)) {
+				return
+			}
+		}
+	}
+}
+
+// Offsets returns the sequence of offsets, see Chunks.
+//
+// 返回偏移值的序列，参见 Chunks
+func Offsets(limit, total int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for start := range Chunks(limit, total) {
+			if !yield(start) {
+				return
+			}
+		}
+	}
+}
+
+// Pages returns the sequence of page descriptors, see Chunks, e.g.:
+//
+//	for page := range data.Pages(20, total) {
+//		rows, err := db.Query("SELECT ... LIMIT ? OFFSET ?", 20, page.Start)
+//		...
+//	}
+//
+// 返回分页描述的序列，参见 Chunks
+func Pages(capacity, total int) iter.Seq[*Bounds] {
+	return func(yield func(*Bounds) bool) {
+		number := 0
+		for start, end := range Chunks(capacity, total) {
+			number++
+			if !yield(&Bounds{Number: number, Start: start, End: end}) {
+				return
+			}
+		}
+	}
+}
+
+// Fetch returns the sequence of items loaded page by page, load is called for each page and the items
+// are yielded one by one, the sequence stops on an empty page, the error of load or ctx is yielded as
+// the last element, e.g.:
+//
+//	for item, err := range data.Fetch(ctx, 100, load) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// 返回逐页加载的数据项序列，每页调用 load 加载并逐个产出数据项，遇到空页时结束，load 或 ctx 的错误作为最后一个元素产出
+func Fetch[T any](ctx context.Context, capacity int, load func(ctx context.Context, page *Bounds) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for page := range Pages(capacity, -1) {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, err := load(ctx, page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if len(items) == 0 {
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
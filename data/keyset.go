@@ -0,0 +1,154 @@
+package data
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrorCursorInvalid = errors.New("data: invalid cursor")
+	ErrorSecretInvalid = errors.New("data: cursor secret too short")
+)
+
+// Order is the order of keyset pagination.
+//
+// 键集分页的排序方向
+type Order int
+
+const (
+	Ascending  Order = iota // 升序
+	Descending              // 降序
+)
+
+// Operator returns the comparison operator to select the records after the key, ">" for Ascending and
+// "<" for Descending, e.g. "WHERE (created, id) > (?, ?)".
+//
+// 返回选取键之后记录的比较运算符，升序为 ">"，降序为 "<"，例如 "WHERE (created, id) > (?, ?)"
+func (o Order) Operator() string {
+	if o == Descending {
+		return "<"
+	}
+	return ">"
+}
+
+// Direction returns the direction of ORDER BY, "ASC" or "DESC".
+//
+// 返回 ORDER BY 的排序方向，"ASC" 或 "DESC"
+func (o Order) Direction() string {
+	if o == Descending {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// Keyset continuous call f with the last seen key until it returns false or error, after is the key from
+// which to start, nil starts from the first page. The key K may be composite, e.g. *tuples.Pair[time.Time, int64].
+// f returns the last key of the page, or nil if the page is empty (e.g. the final page when the record count
+// is a multiple of the page size), in which case the previous key is kept. last is the last seen key which can
+// be encoded as the cursor of the next page.
+// Note: ⚠️Use for statement whenever possible, and pay attention to boundary conditions, and to avoid dead loops
+//
+// 键集（游标）分页，持续调用 f 直到其返回 false 或 error，after 为开始的键，nil 从第一页开始，f 的参数 after 为上一页的最后一个键。
+// 键 K 可以是组合键，例如 *tuples.Pair[time.Time, int64]。f 返回本页的最后一个键，空页（例如记录数恰为页大小整数倍时的最后一页）
+// 返回 nil，此时保留之前的键。last 为最后一个键，可编码为下一页的游标。
+// 注意，⚠️尽量使用 for 循环语句，注意边界条件，避免死循环
+func Keyset[K any](after *K, f func(after *K) (last *K, next bool, err error)) (count int, last *K, err error) {
+	last = after
+	for {
+		count++
+		var (
+			k    *K
+			next bool
+		)
+		k, next, err = f(last)
+		if err != nil {
+			break
+		}
+		if k != nil {
+			last = k
+		}
+		if !next {
+			break
+		}
+	}
+	return
+}
+
+// Cursor is the position of keyset pagination.
+//
+// 键集分页的位置
+type Cursor[K any] struct {
+	Key   K     `json:"k"` // 最后一个键
+	Order Order `json:"o"` // 排序方向
+}
+
+// CursorCodec encodes the cursor as an opaque, tamper-evident token signed by HMAC-SHA256, which can be
+// handed to API clients and decoded again.
+//
+// 游标编解码器，将游标编码为不透明且可检测篡改（HMAC-SHA256 签名）的令牌，可交给 API 客户端并再次解码
+type CursorCodec[K any] struct {
+	secret []byte
+}
+
+// MinSecretSize is the minimum size in bytes of the cursor secret, the size of the SHA-256 digest.
+//
+// 游标密钥的最小字节数，即 SHA-256 摘要的长度
+const MinSecretSize = sha256.Size
+
+// NewCursorCodec creates a cursor codec with the secret key, returns ErrorSecretInvalid if the secret is
+// shorter than MinSecretSize.
+//
+// 使用密钥创建游标编解码器，密钥短于 MinSecretSize 时返回 ErrorSecretInvalid
+func NewCursorCodec[K any](secret []byte) (*CursorCodec[K], error) {
+	if len(secret) < MinSecretSize {
+		return nil, ErrorSecretInvalid
+	}
+	return &CursorCodec[K]{secret: bytes.Clone(secret)}, nil
+}
+
+func (c *CursorCodec[K]) sign(payload []byte) []byte {
+	m := hmac.New(sha256.New, c.secret)
+	m.Write(payload)
+	return m.Sum(nil)
+}
+
+// Encode encodes the cursor as a token.
+//
+// 将游标编码为令牌
+func (c *CursorCodec[K]) Encode(cursor *Cursor[K]) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	e := base64.RawURLEncoding
+	return e.EncodeToString(payload) + "." + e.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode decodes the token, returns ErrorCursorInvalid if the token is malformed or tampered.
+//
+// 解码令牌，令牌格式错误或被篡改时返回 ErrorCursorInvalid
+func (c *CursorCodec[K]) Decode(token string) (*Cursor[K], error) {
+	p, s, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrorCursorInvalid
+	}
+	e := base64.RawURLEncoding
+	payload, err := e.DecodeString(p)
+	if err != nil {
+		return nil, ErrorCursorInvalid
+	}
+	sum, err := e.DecodeString(s)
+	if err != nil || !hmac.Equal(sum, c.sign(payload)) {
+		return nil, ErrorCursorInvalid
+	}
+	cursor := &Cursor[K]{}
+	if err = json.Unmarshal(payload, cursor); err != nil {
+		return nil, ErrorCursorInvalid
+	}
+	return cursor, nil
+}
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKeysetEmptyLastPage(t *testing.T) {
+	// 记录数恰为页大小的整数倍，最后一页为空
+	keys := []int{1, 2, 3, 4}
+	var seen []int
+	count, last, err := Keyset[int](nil, func(after *int) (*int, bool, error) {
+		i := 0
+		if after != nil {
+			i = slices.Index(keys, *after) + 1
+		}
+		page := keys[i:min(i+2, len(keys))]
+		seen = append(seen, page...)
+		if len(page) == 0 {
+			return nil, false, nil
+		}
+		return &page[len(page)-1], len(page) == 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || !slices.Equal(seen, keys) {
+		t.Errorf("want 3 pages of %v, got %d pages of %v", keys, count, seen)
+	}
+	if last == nil || *last != 4 {
+		t.Errorf("want last key 4 kept after the empty page, got %v", last)
+	}
+}
@@ -0,0 +1,238 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrorPageInvalid    = errors.New("data: invalid page")
+	ErrorPageOutOfRange = errors.New("data: page out of range")
+)
+
+const (
+	PageParam = "page" // 默认的页号查询参数名
+	SizeParam = "size" // 默认的分页大小查询参数名
+)
+
+// Page is the metadata of a page built from page number, size and total number of records.
+//
+// 分页元数据，由页号、分页大小与数据记录总数构成
+type Page struct {
+	Number int `json:"number"` // 1 开始的页号
+	Size   int `json:"size"`   // 分页大小
+	Total  int `json:"total"`  // 数据记录总数
+}
+
+// NewPage creates the page, returns ErrorPageInvalid if number or size is less than 1 or total is less
+// than 0, returns ErrorPageOutOfRange if number is beyond the last page, use Clamp to correct it.
+//
+// 创建分页，页号或分页大小小于 1 或总数小于 0 时返回 ErrorPageInvalid，页号超出最后一页时返回 ErrorPageOutOfRange，
+// 可使用 Clamp 修正
+func NewPage(number, size, total int) (*Page, error) {
+	p := &Page{Number: number, Size: size, Total: total}
+	if number < 1 || size < 1 || total < 0 {
+		return p, fmt.Errorf("%w: number %d, size %d, total %d", ErrorPageInvalid, number, size, total)
+	}
+	if number > p.Last() {
+		return p, fmt.Errorf("%w: number %d, last %d", ErrorPageOutOfRange, number, p.Last())
+	}
+	return p, nil
+}
+
+// ParsePage parses the page number and size of user input, e.g. the query parameters, empty number is 1,
+// empty size is fallback, the size greater than limit is clamped to limit (limit < 1 no limit).
+// Like NewPage, the page is never nil, the malformed number or size is replaced by its default and the
+// page is clamped, together with ErrorPageInvalid.
+//
+// 解析用户输入（例如查询参数）的页号与分页大小，页号为空时为 1，分页大小为空时为 fallback，
+// 分页大小超过 limit 时修正为 limit（limit 小于 1 表示不限制）。与 NewPage 相同，返回的分页不为 nil，
+// 格式错误的页号或分页大小替换为默认值，分页修正到有效范围内，同时返回 ErrorPageInvalid
+func ParsePage(number, size string, total, fallback, limit int) (*Page, error) {
+	n, s := 1, fallback
+	var errs []error
+	if number = strings.TrimSpace(number); number != "" {
+		if v, err := strconv.Atoi(number); err == nil {
+			n = v
+		} else {
+			errs = append(errs, fmt.Errorf("%w: number %q", ErrorPageInvalid, number))
+		}
+	}
+	if size = strings.TrimSpace(size); size != "" {
+		if v, err := strconv.Atoi(size); err == nil {
+			s = v
+		} else {
+			errs = append(errs, fmt.Errorf("%w: size %q", ErrorPageInvalid, size))
+		}
+	}
+	if limit > 0 && s > limit {
+		s = limit
+	}
+	if len(errs) > 0 {
+		return (&Page{Number: n, Size: s, Total: total}).Clamp(), errors.Join(errs...)
+	}
+	return NewPage(n, s, total)
+}
+
+// Clamp returns a copy of the page with the number and size clamped into the valid range.
+//
+// 返回页号与分页大小修正到有效范围内的副本
+func (p *Page) Clamp() *Page {
+	c := &Page{Number: p.Number, Size: max(p.Size, 1), Total: max(p.Total, 0)}
+	c.Number = min(max(c.Number, 1), c.Last())
+	return c
+}
+
+// Offset returns the offset of the first record of the page.
+//
+// 返回本页第一条数据记录的偏移
+func (p *Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Limit returns the maximum number of records of the page, i.e. the size.
+//
+// 返回本页的最大数据记录数，即分页大小
+func (p *Page) Limit() int {
+	return p.Size
+}
+
+// Pages returns the number of pages, 0 if there is no record.
+//
+// 返回总页数，没有数据记录时为 0
+func (p *Page) Pages() int {
+	if p.Size < 1 {
+		return 0
+	}
+	return chunks(p.Size, p.Total)
+}
+
+// First returns the number of the first page.
+//
+// 返回第一页的页号
+func (p *Page) First() int {
+	return 1
+}
+
+// Last returns the number of the last page, 1 if there is no record.
+//
+// 返回最后一页的页号，没有数据记录时为 1
+func (p *Page) Last() int {
+	return max(p.Pages(), 1)
+}
+
+// HasPrev reports whether there is a previous page.
+//
+// 是否有上一页
+func (p *Page) HasPrev() bool {
+	return p.Number > 1
+}
+
+// HasNext reports whether there is a next page.
+//
+// 是否有下一页
+func (p *Page) HasNext() bool {
+	return p.Number < p.Pages()
+}
+
+// Prev returns the number of the previous page, 0 if none.
+//
+// 返回上一页的页号，没有则返回 0
+func (p *Page) Prev() int {
+	if !p.HasPrev() {
+		return 0
+	}
+	return p.Number - 1
+}
+
+// Next returns the number of the next page, 0 if none.
+//
+// 返回下一页的页号，没有则返回 0
+func (p *Page) Next() int {
+	if !p.HasNext() {
+		return 0
+	}
+	return p.Number + 1
+}
+
+// Bounds returns the descriptor of the page, the end is clamped to the last record.
+//
+// 返回本页的分页描述，结束位置不超过最后一条数据记录
+func (p *Page) Bounds() *Bounds {
+	start := p.Offset()
+	return &Bounds{Number: p.Number, Start: start, End: last(p.Total, start+p.Size-1)}
+}
+
+// Link returns the RFC 8288 Link header of the first, prev, next and last pages, the page number and
+// size are set to the query parameters of base, empty names use PageParam and SizeParam, e.g.:
+//
+//	w.Header().Set("Link", page.Link(r.URL, "", ""))
+//
+// 返回第一页、上一页、下一页与最后一页的 RFC 8288 Link 头，页号与分页大小设置到 base 的查询参数中，
+// 参数名为空时使用 PageParam 与 SizeParam
+func (p *Page) Link(base *url.URL, number, size string) string {
+	if number == "" {
+		number = PageParam
+	}
+	if size == "" {
+		size = SizeParam
+	}
+	var links []string
+	add := func(n int, rel string) {
+		u := *base
+		q := u.Query()
+		q.Set(number, strconv.Itoa(n))
+		q.Set(size, strconv.Itoa(p.Size))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+	add(p.First(), "first")
+	if p.HasPrev() {
+		add(p.Prev(), "prev")
+	}
+	if p.HasNext() {
+		add(p.Next(), "next")
+	}
+	add(p.Last(), "last")
+	return strings.Join(links, ", ")
+}
+
+// MarshalJSON marshals the page with the computed navigation fields.
+//
+// 序列化分页，包含计算得出的导航字段
+func (p *Page) MarshalJSON() ([]byte, error) {
+	type page Page
+	return json.Marshal(&struct {
+		*page
+		Pages int `json:"pages"`          // 总页数
+		Prev  int `json:"prev,omitempty"` // 上一页页号
+		Next  int `json:"next,omitempty"` // 下一页页号
+	}{
+		page:  (*page)(p),
+		Pages: p.Pages(),
+		Prev:  p.Prev(),
+		Next:  p.Next(),
+	})
+}
+
+// Envelope is the JSON envelope of a page of items for REST responses.
+//
+// 用于 REST 响应的分页数据 JSON 信封
+type Envelope[T any] struct {
+	Items []T   `json:"items"` // 本页数据项
+	Page  *Page `json:"page"`  // 分页元数据
+}
+
+// NewEnvelope creates the envelope of the items of the page, nil items are marshalled as an empty array.
+//
+// 创建分页数据信封，items 为 nil 时序列化为空数组
+func NewEnvelope[T any](items []T, page *Page) *Envelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Envelope[T]{Items: items, Page: page}
+}
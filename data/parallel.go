@@ -0,0 +1,254 @@
+package data
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+)
+
+type parallelOptions struct {
+	ordered bool
+}
+
+// ParallelOption is the option of the parallel chunk processing.
+//
+// 并发分块处理选项
+type ParallelOption func(o *parallelOptions)
+
+// Ordered to receive the results in chunk order, otherwise the results are received as soon as possible.
+// Note: results are always received in chunk order when the total is unknown, since a later chunk may be
+// beyond the end of data until all previous chunks have returned next.
+//
+// 按块顺序接收结果，否则尽快接收结果。
+// 注意：数据总量未知时总是按块顺序接收结果，因为在之前的块全部返回 next 之前，之后的块可能超出了数据的末尾
+func Ordered() ParallelOption {
+	return func(o *parallelOptions) {
+		o.ordered = true
+	}
+}
+
+type outcome[R any] struct {
+	index int
+	value R
+	err   error
+}
+
+// parallel calls f for chunk index 0, 1, 2, ... concurrently by workers, chunks is the number of chunks,
+// less than 0 if unknown. collect is called outside the lock by one goroutine at a time.
+func parallel[R any](ctx context.Context, workers, chunks int, f func(ctx context.Context, index int) (v R, next bool, err error),
+	collect func(index int, v R), opts []ParallelOption) (count int, err error) {
+	if chunks == 0 {
+		return
+	}
+	o := &parallelOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	known := chunks > 0
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mutex      sync.Mutex
+		wg         sync.WaitGroup
+		issued     int
+		frontier   int // 之前的块均已完成并返回 next
+		stop       = math.MaxInt
+		results    = make(map[int]*outcome[R])
+		inflight   = make(map[int]context.CancelFunc)
+		ready      []*outcome[R] // 待 collect 接收的结果
+		delivering bool
+	)
+	if known {
+		stop = chunks
+	}
+	// fail stops at chunk i with the error, must be called with mutex held
+	fail := func(i int, e error) {
+		if err == nil {
+			err = e
+		}
+		stop = min(stop, i)
+		cancel()
+	}
+	// complete handles the result of chunk i, must be called with mutex held
+	complete := func(i int, v R, next bool, e error) {
+		delete(inflight, i)
+		if i >= stop {
+			// 超出数据末尾的块，丢弃
+			return
+		}
+		if e != nil {
+			if known {
+				fail(i, e)
+				return
+			}
+			// 总量未知时，之前的块可能返回 false 使该块超出数据末尾，因此只取消之后的块，
+			// 待之前的块全部返回 next 后才确定错误
+			stop = i + 1
+			for j, c := range inflight {
+				if j > i {
+					c()
+				}
+			}
+			results[i] = &outcome[R]{index: i, err: e}
+		} else {
+			if !next {
+				stop = i + 1
+				for j, c := range inflight {
+					if j > i {
+						c()
+					}
+				}
+			}
+			if known && !o.ordered {
+				ready = append(ready, &outcome[R]{index: i, value: v})
+				results[i] = nil
+			} else {
+				results[i] = &outcome[R]{index: i, value: v}
+			}
+		}
+		for frontier < stop {
+			r, ok := results[frontier]
+			if !ok {
+				break
+			}
+			delete(results, frontier)
+			if r != nil {
+				if r.err != nil {
+					fail(frontier, r.err)
+					break
+				}
+				ready = append(ready, r)
+			}
+			frontier++
+		}
+	}
+	// deliver passes the ready results to collect outside the lock, one goroutine at a time
+	deliver := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if delivering {
+			// 正在接收的协程负责接收新的结果
+			return
+		}
+		delivering = true
+		for len(ready) > 0 {
+			rs := ready
+			ready = nil
+			mutex.Unlock()
+			for _, r := range rs {
+				count++
+				if collect != nil {
+					collect(r.index, r.value)
+				}
+			}
+			mutex.Lock()
+		}
+		delivering = false
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mutex.Lock()
+				i := issued
+				if i >= stop || ctx.Err() != nil {
+					mutex.Unlock()
+					return
+				}
+				issued++
+				cctx, c := context.WithCancel(ctx)
+				inflight[i] = c
+				mutex.Unlock()
+
+				v, next, e := f(cctx, i)
+				c()
+
+				mutex.Lock()
+				complete(i, v, next, e)
+				mutex.Unlock()
+				deliver()
+			}
+		}()
+	}
+	wg.Wait()
+	if err == nil && frontier < stop {
+		// 未完成全部的块，ctx 被调用方取消
+		err = ctx.Err()
+	}
+	return
+}
+
+// ParallelChunk is the concurrent variant of Chunk, calls f concurrently by workers (less than 1 uses
+// GOMAXPROCS) until it returns false or error, total is the number of data records, less than 0 if unknown.
+// New chunks are not issued after the first error or a chunk returns false, the in-flight chunks are
+// cancelled if they are beyond the end or an error occurred. When the total is unknown, an error is
+// returned only if all the previous chunks returned next, the errors beyond the end are dropped.
+// The results of the chunks are received by collect (may be nil) one by one outside the lock, count is
+// the number of received chunks.
+//
+// Chunk 的并发版本，使用 workers 个协程（小于 1 时使用 GOMAXPROCS）并发调用 f 直到其返回 false 或 error，
+// total 为数据记录总数，小于 0 表示未知。出现第一个错误或某块返回 false 后不再分配新的块，并取消超出末尾或出错时正在执行的块。
+// 总量未知时，仅当之前的块全部返回 next 时才返回该块的错误，超出末尾的块的错误被丢弃。
+// 各块的结果在锁外依次由 collect（可为 nil）接收，count 为已接收的块数。
+func ParallelChunk[R any](ctx context.Context, size, workers, total int, f func(ctx context.Context, start, end int) (v R, next bool, err error),
+	collect func(index int, v R), opts ...ParallelOption) (count int, err error) {
+	if size < 1 {
+		// 不执行
+		return
+	}
+	return parallel(ctx, workers, chunks(size, total), func(ctx context.Context, i int) (R, bool, error) {
+		return f(ctx, i*size, last(total, (i+1)*size-1))
+	}, collect, opts)
+}
+
+// ParallelOffset is the concurrent variant of Offset, see ParallelChunk.
+//
+// Offset 的并发版本，参见 ParallelChunk
+func ParallelOffset[R any](ctx context.Context, limit, workers, total int, f func(ctx context.Context, offset int) (v R, next bool, err error),
+	collect func(index int, v R), opts ...ParallelOption) (count int, err error) {
+	if limit < 1 {
+		// 不执行
+		return
+	}
+	return parallel(ctx, workers, chunks(limit, total), func(ctx context.Context, i int) (R, bool, error) {
+		return f(ctx, i*limit)
+	}, collect, opts)
+}
+
+// ParallelPaged is the concurrent variant of Paged, see ParallelChunk.
+//
+// Paged 的并发版本，参见 ParallelChunk
+func ParallelPaged[R any](ctx context.Context, capacity, workers, total int, f func(ctx context.Context, number, start, end int) (v R, next bool, err error),
+	collect func(index int, v R), opts ...ParallelOption) (count int, err error) {
+	if capacity < 1 {
+		// 不执行
+		return
+	}
+	return parallel(ctx, workers, chunks(capacity, total), func(ctx context.Context, i int) (R, bool, error) {
+		return f(ctx, i+1, i*capacity, last(total, (i+1)*capacity-1))
+	}, collect, opts)
+}
+
+// chunks returns the number of chunks of total records, -1 if total is unknown.
+func chunks(size, total int) int {
+	if total < 0 {
+		return -1
+	}
+	return (total + size - 1) / size
+}
+
+// last clamps the end position to the last record if total is known.
+func last(total, end int) int {
+	if total >= 0 && end > total-1 {
+		return total - 1
+	}
+	return end
+}
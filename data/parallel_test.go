@@ -0,0 +1,94 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelOffsetError(t *testing.T) {
+	failed := errors.New("failed")
+	c, err := ParallelOffset(context.Background(), 10, 4, -1, func(ctx context.Context, offset int) (int, bool, error) {
+		if offset == 30 {
+			return 0, false, failed
+		}
+		if offset > 30 {
+			// 出错后正在执行的块被取消
+			<-ctx.Done()
+			return 0, false, ctx.Err()
+		}
+		time.Sleep(time.Millisecond)
+		return offset, true, nil
+	}, nil)
+	if !errors.Is(err, failed) {
+		t.Errorf("want %v, got %v", failed, err)
+	}
+	if c > 3 {
+		t.Errorf("want at most 3 chunks before the error, got %d", c)
+	}
+}
+
+func TestParallelChunkCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	_, err := ParallelChunk(ctx, 10, 2, -1, func(ctx context.Context, start, end int) (int, bool, error) {
+		if calls.Add(1) == 5 {
+			cancel()
+		}
+		return 0, true, nil
+	}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestParallelOffsetErrorPastEnd(t *testing.T) {
+	failed := errors.New("failed")
+	var collected []int
+	c, err := ParallelOffset(context.Background(), 10, 2, -1, func(ctx context.Context, offset int) (int, bool, error) {
+		if offset > 0 {
+			// 该块随后被证实超出数据末尾，其错误应被丢弃
+			return 0, false, failed
+		}
+		select {
+		case <-ctx.Done():
+			return 0, false, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+		return offset, false, nil
+	}, func(index int, v int) {
+		collected = append(collected, index)
+	})
+	if err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+	if c != 1 || len(collected) != 1 || collected[0] != 0 {
+		t.Errorf("want chunk 0 collected, got %d %v", c, collected)
+	}
+}
+
+func TestParallelChunkSlowCollect(t *testing.T) {
+	var calls atomic.Int32
+	all := make(chan struct{})
+	c, err := ParallelChunk(context.Background(), 10, 2, 80, func(ctx context.Context, start, end int) (int, bool, error) {
+		if calls.Add(1) == 8 {
+			close(all)
+		}
+		return start, true, nil
+	}, func(index int, v int) {
+		if index != 0 {
+			return
+		}
+		// collect 在锁外调用，缓慢的 collect 不阻塞其它块的执行
+		select {
+		case <-all:
+		case <-time.After(time.Second):
+			t.Error("chunks stalled by collect")
+		}
+	})
+	if err != nil || c != 8 {
+		t.Errorf("want 8 chunks, got %d (%v)", c, err)
+	}
+}
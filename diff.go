@@ -0,0 +1,250 @@
+package golang
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrorEditScriptInvalid = errors.New("golang: invalid edit script")
+)
+
+// EditKind is the kind of edit operation.
+//
+// 编辑操作类型
+type EditKind int
+
+const (
+	EditEqual  EditKind = iota // 相同
+	EditInsert                 // 插入
+	EditDelete                 // 删除
+)
+
+// String returns the name of the edit kind.
+//
+// 返回编辑操作类型名称
+func (k EditKind) String() string {
+	switch k {
+	case EditEqual:
+		return "equal"
+	case EditInsert:
+		return "insert"
+	case EditDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("EditKind(%d)", int(k))
+	}
+}
+
+// Edit is an operation of the edit script which transforms the old slice into the new one.
+//
+// 将旧切片转换为新切片的编辑脚本中的一个操作
+type Edit[E any] struct {
+	Kind  EditKind `json:"kind"`  // 操作类型
+	Old   int      `json:"old"`   // 元素在旧切片中的索引，EditInsert 为 -1
+	New   int      `json:"new"`   // 元素在新切片中的索引，EditDelete 为 -1
+	Value E        `json:"value"` // 元素值，EditEqual 与 EditDelete 为旧切片中的值，EditInsert 为新切片中的值
+}
+
+// diffMinCost is the minimum number of edit steps searched by bisect before giving up the shortest path.
+const diffMinCost = 256
+
+// differ finds the shortest edit script by the linear space variant of the Myers algorithm.
+type differ[E any] struct {
+	a, b   []E
+	equal  func(x, y E) bool
+	cost   int // bisect 搜索的最大编辑步数，超过后使用启发式的分割点
+	script []Edit[E]
+}
+
+func (d *differ[E]) emit(kind EditKind, x, y, n int) {
+	for i := 0; i < n; i++ {
+		switch kind {
+		case EditEqual:
+			d.script = append(d.script, Edit[E]{Kind: kind, Old: x + i, New: y + i, Value: d.a[x+i]})
+		case EditInsert:
+			d.script = append(d.script, Edit[E]{Kind: kind, Old: -1, New: y + i, Value: d.b[y+i]})
+		case EditDelete:
+			d.script = append(d.script, Edit[E]{Kind: kind, Old: x + i, New: -1, Value: d.a[x+i]})
+		}
+	}
+}
+
+// compare emits the edit script of a[aLo:aHi] and b[bLo:bHi].
+func (d *differ[E]) compare(aLo, aHi, bLo, bHi int) {
+	// 相同的前缀
+	p := 0
+	for aLo+p < aHi && bLo+p < bHi && d.equal(d.a[aLo+p], d.b[bLo+p]) {
+		p++
+	}
+	d.emit(EditEqual, aLo, bLo, p)
+	aLo, bLo = aLo+p, bLo+p
+	// 相同的后缀，最后输出
+	s := 0
+	for aLo < aHi-s && bLo < bHi-s && d.equal(d.a[aHi-s-1], d.b[bHi-s-1]) {
+		s++
+	}
+	aHi, bHi = aHi-s, bHi-s
+
+	switch {
+	case aLo == aHi:
+		d.emit(EditInsert, aLo, bLo, bHi-bLo)
+	case bLo == bHi:
+		d.emit(EditDelete, aLo, bLo, aHi-aLo)
+	default:
+		if x, y, ok := d.bisect(aLo, aHi, bLo, bHi); ok {
+			d.compare(aLo, x, bLo, y)
+			d.compare(x, aHi, y, bHi)
+		} else {
+			d.emit(EditDelete, aLo, bLo, aHi-aLo)
+			d.emit(EditInsert, aLo, bLo, bHi-bLo)
+		}
+	}
+	d.emit(EditEqual, aHi, bHi, s)
+}
+
+// bisect finds the middle snake of a[aLo:aHi] and b[bLo:bHi] by searching from both ends, returns the
+// split point. If the search exceeds the cost, the furthest reaching point of the forward search is
+// returned instead, like the TOO_EXPENSIVE heuristic of GNU diff, the script is then no longer minimal.
+func (d *differ[E]) bisect(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	limit := (n + m + 1) / 2
+	offset, length := limit, 2*limit+2
+	forward, backward := make([]int, length), make([]int, length)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	// 差值为奇数时前向路径与后向路径重叠，否则在后向搜索时检查重叠
+	odd := delta%2 != 0
+	var fStart, fEnd, bStart, bEnd, bestX, bestY int
+	for k := 0; k < limit; k++ {
+		if k >= d.cost {
+			if bestX+bestY == 0 || bestX == n && bestY == m {
+				return 0, 0, false
+			}
+			return aLo + bestX, bLo + bestY, true
+		}
+		for k1 := -k + fStart; k1 <= k-fEnd; k1 += 2 {
+			i := offset + k1
+			var x int
+			if k1 == -k || k1 != k && forward[i-1] < forward[i+1] {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k1
+			for x < n && y < m && d.equal(d.a[aLo+x], d.b[bLo+y]) {
+				x++
+				y++
+			}
+			forward[i] = x
+			if x <= n && y <= m && x+y > bestX+bestY {
+				bestX, bestY = x, y
+			}
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				if j := offset + delta - k1; j >= 0 && j < length && backward[j] != -1 && x >= n-backward[j] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+		for k2 := -k + bStart; k2 <= k-bEnd; k2 += 2 {
+			i := offset + k2
+			var x int
+			if k2 == -k || k2 != k && backward[i-1] < backward[i+1] {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+			y := x - k2
+			for x < n && y < m && d.equal(d.a[aHi-x-1], d.b[bHi-y-1]) {
+				x++
+				y++
+			}
+			backward[i] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				if j := offset + delta - k2; j >= 0 && j < length && forward[j] != -1 {
+					x1 := forward[j]
+					y1 := offset + x1 - j
+					if x1 >= n-x {
+						return aLo + x1, bLo + y1, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Diff returns the shortest edit script which transforms a into b, the elements are compared by equal.
+// It runs in O((N+M)D) time and O(N+M) space by the Myers algorithm, where D is the number of the inserted
+// and deleted elements, so it is fast for the large slices with few differences. The search is capped
+// for the large slices with many differences, the script is then valid but may not be the shortest.
+//
+// 返回将 a 转换为 b 的最短编辑脚本，使用 equal 比较元素。采用 Myers 算法，时间复杂度为 O((N+M)D)，空间复杂度为 O(N+M)，
+// 其中 D 为插入与删除的元素个数，因此对差异较少的大切片也很快。对差异很多的大切片限制搜索代价，此时编辑脚本有效但不一定最短。
+func Diff[E any](a, b []E, equal func(x, y E) bool) []Edit[E] {
+	cost := diffMinCost
+	for c := len(a) + len(b); c > diffMinCost*diffMinCost; c >>= 2 {
+		// 约为 sqrt(N+M)
+		cost <<= 1
+	}
+	d := &differ[E]{a: a, b: b, equal: equal, cost: cost, script: make([]Edit[E], 0, max(len(a), len(b)))}
+	d.compare(0, len(a), 0, len(b))
+	return d.script
+}
+
+// ApplyEdits applies the edit script to a and returns the new slice, the script must be produced from a,
+// otherwise returns ErrorEditScriptInvalid.
+//
+// 将编辑脚本应用到 a 并返回新的切片，编辑脚本必须由 a 生成，否则返回 ErrorEditScriptInvalid
+func ApplyEdits[E any](a []E, script []Edit[E]) ([]E, error) {
+	result := make([]E, 0, len(a))
+	i := 0
+	for n, e := range script {
+		switch e.Kind {
+		case EditEqual, EditDelete:
+			if e.Old != i || i >= len(a) {
+				return nil, fmt.Errorf("%w: %d. %s old index %d, want %d", ErrorEditScriptInvalid, n+1, e.Kind, e.Old, i)
+			}
+			if e.Kind == EditEqual {
+				result = append(result, a[i])
+			}
+			i++
+		case EditInsert:
+			result = append(result, e.Value)
+		default:
+			return nil, fmt.Errorf("%w: %d. %s", ErrorEditScriptInvalid, n+1, e.Kind)
+		}
+	}
+	if i != len(a) {
+		return nil, fmt.Errorf("%w: %d elements not covered", ErrorEditScriptInvalid, len(a)-i)
+	}
+	return result, nil
+}
+
+// Changes summarises the edit script into the added and removed elements, in the order of the script.
+//
+// 汇总编辑脚本，按脚本顺序返回新增与删除的元素
+func Changes[E any](script []Edit[E]) (added, removed []E) {
+	for _, e := range script {
+		switch e.Kind {
+		case EditInsert:
+			added = append(added, e.Value)
+		case EditDelete:
+			removed = append(removed, e.Value)
+		}
+	}
+	return
+}
@@ -0,0 +1,165 @@
+package golang
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func equalInt(x, y int) bool {
+	return x == y
+}
+
+// lcs returns the length of the longest common subsequence by dynamic programming.
+func lcs(a, b []int) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
+
+func checkDiff(t *testing.T, a, b []int) {
+	t.Helper()
+	script := Diff(a, b, equalInt)
+	got, err := ApplyEdits(a, script)
+	if err != nil || !slices.Equal(got, b) {
+		t.Fatalf("ApplyEdits(%v, Diff(%v, %v)): got %v, %v", a, a, b, got, err)
+	}
+	equals, j := 0, 0
+	for _, e := range script {
+		switch e.Kind {
+		case EditEqual:
+			equals++
+			if a[e.Old] != b[e.New] {
+				t.Fatalf("equal edit %+v of different elements", e)
+			}
+			fallthrough
+		case EditInsert:
+			if e.New != j {
+				t.Fatalf("edit %+v: want new index %d", e, j)
+			}
+			j++
+		}
+	}
+	if want := lcs(a, b); equals != want {
+		t.Fatalf("Diff(%v, %v): want %d equal elements, got %d", a, b, want, equals)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, r := range []struct {
+		a, b []int
+	}{
+		{nil, nil},
+		{nil, []int{1, 2}},
+		{[]int{1, 2}, nil},
+		{[]int{1, 2, 3}, []int{1, 2, 3}},
+		{[]int{1, 2, 3}, []int{4, 5, 6}},
+		{[]int{1, 2, 3, 4, 5}, []int{1, 3, 4, 6, 5}},
+		{[]int{1, 2, 1, 2, 1}, []int{2, 1, 2, 1, 2}},
+	} {
+		checkDiff(t, r.a, r.b)
+	}
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 500; i++ {
+		a, b := make([]int, rnd.IntN(20)), make([]int, rnd.IntN(20))
+		for j := range a {
+			a[j] = rnd.IntN(4)
+		}
+		for j := range b {
+			b[j] = rnd.IntN(4)
+		}
+		checkDiff(t, a, b)
+	}
+}
+
+func TestDiffChanges(t *testing.T) {
+	script := Diff([]string{"a", "b", "c"}, []string{"a", "c", "d"}, func(x, y string) bool { return x == y })
+	added, removed := Changes(script)
+	if !slices.Equal(added, []string{"d"}) || !slices.Equal(removed, []string{"b"}) {
+		t.Errorf("want [d] added and [b] removed, got %v, %v", added, removed)
+	}
+	if _, err := ApplyEdits([]string{"x"}, script); !errors.Is(err, ErrorEditScriptInvalid) {
+		t.Errorf("want %v, got %v", ErrorEditScriptInvalid, err)
+	}
+}
+
+func largeDiffInput(n, changes int) (a, b []int) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	a = make([]int, n)
+	for i := range a {
+		a[i] = i
+	}
+	b = slices.Clone(a)
+	for i := 0; i < changes; i++ {
+		p := rnd.IntN(len(b))
+		if i%2 == 0 {
+			b = slices.Delete(b, p, p+1)
+		} else {
+			b = slices.Insert(b, p, -i)
+		}
+	}
+	return
+}
+
+func TestDiffLarge(t *testing.T) {
+	a, b := largeDiffInput(50000, 200)
+	script := Diff(a, b, equalInt)
+	if got, err := ApplyEdits(a, script); err != nil || !slices.Equal(got, b) {
+		t.Fatalf("ApplyEdits: %v", err)
+	}
+	added, removed := Changes(script)
+	if len(added) != 100 || len(removed) != 100 {
+		t.Errorf("want 100 added and 100 removed, got %d, %d", len(added), len(removed))
+	}
+}
+
+// disjointDiffInput returns two slices without any common element, the worst case of Diff.
+func disjointDiffInput(n int) (a, b []int) {
+	a, b = make([]int, n), make([]int, n)
+	for i := range a {
+		a[i], b[i] = i, n+i
+	}
+	return
+}
+
+func TestDiffExpensive(t *testing.T) {
+	// 超过搜索代价时编辑脚本依然有效
+	rnd := rand.New(rand.NewPCG(5, 6))
+	a, b := make([]int, 5000), make([]int, 5000)
+	for i := range a {
+		a[i], b[i] = rnd.IntN(50), rnd.IntN(50)
+	}
+	x, y := disjointDiffInput(20000)
+	for _, r := range [][2][]int{{a, b}, {x, y}} {
+		if got, err := ApplyEdits(r[0], Diff(r[0], r[1], equalInt)); err != nil || !slices.Equal(got, r[1]) {
+			t.Fatalf("ApplyEdits: %v", err)
+		}
+	}
+}
+
+func BenchmarkDiff(b *testing.B) {
+	b.Run("few", func(b *testing.B) {
+		x, y := largeDiffInput(50000, 200)
+		for i := 0; i < b.N; i++ {
+			Diff(x, y, equalInt)
+		}
+	})
+	b.Run("disjoint", func(b *testing.B) {
+		x, y := disjointDiffInput(20000)
+		for i := 0; i < b.N; i++ {
+			Diff(x, y, equalInt)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package golang
+
+import "github.com/keepitlight/golang/tuples"
+
+// GroupBy groups the elements by the key returned by f, the groups are ordered by the first seen keys and
+// the elements of each group keep the original order.
+//
+// 分组，使用函数 f 返回的键对元素分组，各组按键首次出现的顺序排列，组内元素保持原有顺序
+func GroupBy[E any, K comparable](ss []E, f func(index int, ele E) K) (groups []*tuples.Pair[K, []E]) {
+	positions := make(map[K]int)
+	for i, s := range ss {
+		k := f(i, s)
+		if j, ok := positions[k]; ok {
+			groups[j].B = append(groups[j].B, s)
+			continue
+		}
+		positions[k] = len(groups)
+		groups = append(groups, Tuple(k, []E{s}))
+	}
+	return
+}
+
+// Partition splits the elements into the matching ones (A) and the others (B), both keep the original order.
+//
+// 划分，将元素分为 f 返回 true 的元素（A）与其余元素（B），均保持原有顺序
+func Partition[E any](ss []E, f func(index int, ele E) bool) *tuples.Pair[[]E, []E] {
+	p := &tuples.Pair[[]E, []E]{}
+	for i, s := range ss {
+		if f(i, s) {
+			p.A = append(p.A, s)
+		} else {
+			p.B = append(p.B, s)
+		}
+	}
+	return p
+}
+
+// Chunk splits the slice into the consecutive chunks of size elements, the last chunk may be shorter.
+// The chunks share the memory of the slice with the capacity limited to the chunk, nil if size is less than 1.
+//
+// 分块，将切片分为每 size 个连续元素的块，最后一块可能较短。各块与原切片共用内存，容量限制为块的长度，size 小于 1 时返回 nil
+func Chunk[E any](ss []E, size int) (chunks [][]E) {
+	if size < 1 {
+		return
+	}
+	for i := 0; i < len(ss); i += size {
+		end := min(i+size, len(ss))
+		chunks = append(chunks, ss[i:end:end])
+	}
+	return
+}
+
+// Window returns the sliding windows of size elements which move step elements at a time, only full windows
+// are returned. The windows share the memory of the slice with the capacity limited to the window, nil if
+// size or step is less than 1.
+//
+// 滑动窗口，返回 size 个元素的窗口，每次移动 step 个元素，仅返回完整的窗口。各窗口与原切片共用内存，容量限制为窗口的长度，
+// size 或 step 小于 1 时返回 nil
+func Window[E any](ss []E, size, step int) (windows [][]E) {
+	if size < 1 || step < 1 {
+		return
+	}
+	for i := 0; i+size <= len(ss); i += step {
+		windows = append(windows, ss[i:i+size:i+size])
+	}
+	return
+}
+
+// Zip pairs the elements of a and b with the same index, the length is the shorter one.
+//
+// 将 a 与 b 中相同索引的元素组成二元组，长度为较短者
+func Zip[A, B any](a []A, b []B) []*tuples.Pair[A, B] {
+	result := make([]*tuples.Pair[A, B], min(len(a), len(b)))
+	for i := range result {
+		result[i] = Tuple(a[i], b[i])
+	}
+	return result
+}
+
+// Zip3 groups the elements of a, b and c with the same index, the length is the shortest one.
+//
+// 将 a、b 与 c 中相同索引的元素组成三元组，长度为最短者
+func Zip3[A, B, C any](a []A, b []B, c []C) []*tuples.Triplet[A, B, C] {
+	result := make([]*tuples.Triplet[A, B, C], min(len(a), len(b), len(c)))
+	for i := range result {
+		result[i] = Tuple3(a[i], b[i], c[i])
+	}
+	return result
+}
+
+// Unzip splits the pairs into the slices of the first and second values, nil pairs are zero values.
+//
+// 将二元组拆分为第一个值与第二个值的切片，nil 二元组视为零值
+func Unzip[A, B any](ps []*tuples.Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(ps)), make([]B, len(ps))
+	for i, p := range ps {
+		if p != nil {
+			a[i], b[i] = p.A, p.B
+		}
+	}
+	return a, b
+}
+
+// Unzip3 splits the triplets into the slices of the values, nil triplets are zero values.
+//
+// 将三元组拆分为各值的切片，nil 三元组视为零值
+func Unzip3[A, B, C any](ts []*tuples.Triplet[A, B, C]) ([]A, []B, []C) {
+	a, b, c := make([]A, len(ts)), make([]B, len(ts)), make([]C, len(ts))
+	for i, t := range ts {
+		if t != nil {
+			a[i], b[i], c[i] = t.A, t.B, t.C
+		}
+	}
+	return a, b, c
+}
+
+// CountBy counts the elements by the key returned by f.
+//
+// 使用函数 f 返回的键对元素计数
+func CountBy[E any, K comparable](ss []E, f func(index int, ele E) K) map[K]int {
+	result := make(map[K]int)
+	for i, s := range ss {
+		result[f(i, s)]++
+	}
+	return result
+}
+
+// Frequencies counts the occurrences of each element.
+//
+// 统计每个元素出现的次数
+func Frequencies[E comparable](ss []E) map[E]int {
+	return CountBy(ss, func(_ int, ele E) E { return ele })
+}
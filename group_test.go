@@ -0,0 +1,63 @@
+package golang
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestChunkWindow(t *testing.T) {
+	ss := []int{1, 2, 3, 4, 5, 6, 7}
+	for i, r := range []struct {
+		got  [][]int
+		want [][]int
+	}{
+		{Chunk(ss, 3), [][]int{{1, 2, 3}, {4, 5, 6}, {7}}},
+		{Chunk(ss, 0), nil},
+		{Window(ss, 3, 2), [][]int{{1, 2, 3}, {3, 4, 5}, {5, 6, 7}}},
+		{Window(ss, 3, 3), [][]int{{1, 2, 3}, {4, 5, 6}}},
+		{Window(ss, 8, 1), nil},
+	} {
+		if !slices.EqualFunc(r.got, r.want, slices.Equal[[]int]) {
+			t.Errorf("%d. want %v, got %v", i+1, r.want, r.got)
+		}
+	}
+	// 块的容量受限，追加不会覆盖原切片
+	c := Chunk(ss, 3)
+	_ = append(c[0], 100)
+	if ss[3] != 4 {
+		t.Errorf("append to chunk overwrites the slice: %v", ss)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy([]string{"apple", "bob", "avocado", "cat", "banana"}, func(i int, s string) byte { return s[0] })
+	var keys []byte
+	for _, g := range groups {
+		keys = append(keys, g.A)
+	}
+	if string(keys) != "abc" || !slices.Equal(groups[0].B, []string{"apple", "avocado"}) || !slices.Equal(groups[1].B, []string{"bob", "banana"}) {
+		t.Errorf("GroupBy error: %s %v", keys, groups)
+	}
+}
+
+func TestZipUnzip(t *testing.T) {
+	ps := Zip([]int{1, 2, 3}, []string{"a", "b"})
+	a, b := Unzip(ps)
+	if !slices.Equal(a, []int{1, 2}) || !slices.Equal(b, []string{"a", "b"}) {
+		t.Errorf("Zip/Unzip error: %v %v", a, b)
+	}
+	x, y, z := Unzip3(Zip3([]int{1, 2}, []bool{true, false}, []string{"a", "b", "c"}))
+	if !slices.Equal(x, []int{1, 2}) || !slices.Equal(y, []bool{true, false}) || !slices.Equal(z, []string{"a", "b"}) {
+		t.Errorf("Zip3/Unzip3 error: %v %v %v", x, y, z)
+	}
+}
+
+func TestFrequencies(t *testing.T) {
+	if got, want := Frequencies([]string{"a", "b", "a"}), map[string]int{"a": 2, "b": 1}; !maps.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if got, want := CountBy([]int{1, 2, 3, 4, 5}, func(i, e int) bool { return e%2 == 0 }), map[bool]int{true: 2, false: 3}; !maps.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,207 @@
+package hotfix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrorCommandUnknown = errors.New("hotfix: unknown command")
+	ErrorCommandUsage   = errors.New("hotfix: invalid command usage")
+)
+
+const commandUsage = `usage: hotfix <command> [flags]
+
+commands:
+  status                           show the fixed, latest versions and pending steps
+  plan [-from N] [-to N]           list the pending steps without executing
+  apply [-from N] [-to N]          apply the pending steps up to version N
+  rollback -to N [-force]          roll back to version N
+
+flags:
+  -format text|json                output format, default text
+`
+
+// status is the output of the status command.
+type status struct {
+	Fixed   Version `json:"fixed"`
+	Latest  Version `json:"latest"`
+	Pending Steps   `json:"pending"`
+	Drifts  Drifts  `json:"drifts,omitempty"`
+}
+
+// Command returns a flag-based subcommand handler of the registry, nil uses the default registry.
+// The handler supports status, plan, apply -to N and rollback -to N, writes the output to w in text
+// or JSON format, opts are passed to Patch, Plan and Rollback, e.g.:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "hotfix" {
+//		if err := hotfix.Command(nil, os.Stdout)(ctx, os.Args[2:]); err != nil {
+//			os.Exit(1)
+//		}
+//		return
+//	}
+//
+// 返回注册表的子命令处理函数，r 为 nil 时使用默认注册表。处理函数支持 status、plan、apply -to N 与
+// rollback -to N 子命令，以文本或 JSON 格式将结果输出到 w，opts 传递给 Patch、Plan 与 Rollback。
+func Command(r *Registry, w io.Writer, opts ...Option) func(ctx context.Context, args []string) error {
+	if r == nil {
+		r = Default()
+	}
+	return func(ctx context.Context, args []string) error {
+		if len(args) < 1 {
+			_, _ = io.WriteString(w, commandUsage)
+			return ErrorCommandUsage
+		}
+		name := args[0]
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		fs.SetOutput(w)
+		fs.Usage = func() {
+			_, _ = io.WriteString(w, commandUsage)
+		}
+		var (
+			format = fs.String("format", "text", "output format, text or json")
+			from   = fs.Int("from", 1, "the version from which to start patching")
+			to     = fs.Int("to", -1, "the target version")
+			force  = fs.Bool("force", false, "skip the steps without undo function")
+		)
+		if err := fs.Parse(args[1:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				// -h 已输出用法
+				return nil
+			}
+			return err
+		}
+		if *format != "text" && *format != "json" {
+			return fmt.Errorf("%w: format %q", ErrorCommandUsage, *format)
+		}
+		c := &command{registry: r, w: w, json: *format == "json", opts: opts}
+		if *to > 0 {
+			c.opts = append(c.opts, Target(Version(*to)))
+		}
+		switch name {
+		case "status":
+			return c.status(ctx)
+		case "plan":
+			return c.list(ctx, Version(*from))
+		case "apply":
+			return c.apply(ctx, Version(*from))
+		case "rollback":
+			if *to < 0 {
+				return fmt.Errorf("%w: rollback requires -to", ErrorCommandUsage)
+			}
+			if *force {
+				c.opts = append(c.opts, Force())
+			}
+			return c.rollback(ctx, Version(*to))
+		case "help":
+			_, _ = io.WriteString(w, commandUsage)
+			return nil
+		default:
+			_, _ = io.WriteString(w, commandUsage)
+			return fmt.Errorf("%w: %q", ErrorCommandUnknown, name)
+		}
+	}
+}
+
+type command struct {
+	registry *Registry
+	w        io.Writer
+	json     bool
+	opts     []Option
+}
+
+func (c *command) print(v any, text string) error {
+	if c.json {
+		e := json.NewEncoder(c.w)
+		e.SetIndent("", "  ")
+		return e.Encode(v)
+	}
+	_, err := fmt.Fprintln(c.w, text)
+	return err
+}
+
+func (c *command) plan(ctx context.Context, from Version) (Steps, error) {
+	steps, err := c.registry.Plan(ctx, from, c.opts...)
+	if steps == nil {
+		steps = Steps{}
+	}
+	return steps, err
+}
+
+func (c *command) fixed() (Version, error) {
+	if h := c.registry.options(c.opts).history; h != nil {
+		v := h.Fixed()
+		return v, historyErr(h)
+	}
+	return Omitted, nil
+}
+
+func (c *command) status(ctx context.Context) error {
+	steps, err := c.plan(ctx, 1)
+	if err != nil {
+		return err
+	}
+	fixed, err := c.fixed()
+	if err != nil {
+		return err
+	}
+	s := &status{
+		Fixed:   fixed,
+		Latest:  c.registry.Latest(),
+		Pending: steps,
+	}
+	ds, err := c.registry.Verify(ctx, c.opts...)
+	switch {
+	case err == nil:
+		s.Drifts = ds
+	case errors.Is(err, ErrorFingerprintUnsupported), errors.Is(err, ErrorHistoryUndefined):
+		// 无法校验时不显示偏差
+	default:
+		return err
+	}
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "fixed: %d\nlatest: %d\npending: %d step(s)", s.Fixed, s.Latest, len(s.Pending))
+	if len(s.Drifts) > 0 {
+		_, _ = fmt.Fprintf(&b, "\ndrifts:\n%s", s.Drifts)
+	}
+	return c.print(s, b.String())
+}
+
+func (c *command) list(ctx context.Context, from Version) error {
+	steps, err := c.plan(ctx, from)
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]any{"steps": steps}, steps.String())
+}
+
+func (c *command) apply(ctx context.Context, from Version) error {
+	steps, err := c.plan(ctx, from)
+	if err != nil {
+		return err
+	}
+	if err = c.registry.Patch(ctx, from, c.opts...); err != nil {
+		return err
+	}
+	fixed, err := c.fixed()
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]any{"steps": steps, "fixed": fixed}, fmt.Sprintf("%s\nfixed: %d", steps, fixed))
+}
+
+func (c *command) rollback(ctx context.Context, target Version) error {
+	if err := c.registry.Rollback(ctx, target, c.opts...); err != nil {
+		return err
+	}
+	fixed, err := c.fixed()
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]any{"fixed": fixed}, fmt.Sprintf("fixed: %d", fixed))
+}
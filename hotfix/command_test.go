@@ -0,0 +1,99 @@
+package hotfix
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandErrors(t *testing.T) {
+	h, err := OpenFileHistory(filepath.Join(t.TempDir(), "hotfix.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	failed := errors.New("failed")
+	nop := func(ctx context.Context, version Version, title string) error { return nil }
+	r := NewRegistry()
+	r.SetHistoryProvider(h)
+	r.FixIt(1, "a", nop, Undo(nop))
+	r.FixIt(2, "b", nop)
+	r.FixIt(3, "c", func(ctx context.Context, version Version, title string) error { return failed })
+
+	var out bytes.Buffer
+	run := Command(r, &out)
+	ctx := context.Background()
+	cases := []struct {
+		args []string
+		want error
+	}{
+		{nil, ErrorCommandUsage},
+		{[]string{"unknown"}, ErrorCommandUnknown},
+		{[]string{"status", "-format", "xml"}, ErrorCommandUsage},
+		{[]string{"apply"}, failed},
+		{[]string{"rollback"}, ErrorCommandUsage},
+		{[]string{"rollback", "-to", "0"}, ErrorIrreversible},
+	}
+	for _, c := range cases {
+		out.Reset()
+		if err := run(ctx, c.args); !errors.Is(err, c.want) {
+			t.Errorf("%v: want %v, got %v", c.args, c.want, err)
+		}
+	}
+	if v := h.Fixed(); v != 2 {
+		t.Errorf("want fixed version 2 after the failed apply, got %d", v)
+	}
+
+	// -h 输出用法而不是返回错误
+	out.Reset()
+	if err := run(ctx, []string{"apply", "-h"}); err != nil {
+		t.Errorf("want no error for -h, got %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "usage: hotfix") {
+		t.Errorf("want usage, got %q", out.String())
+	}
+}
+
+func TestCommandStatusError(t *testing.T) {
+	ctx := context.Background()
+	dsn := t.Name() + "/" + t.TempDir()
+	db, err := sql.Open("hotfix-fake", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	h, err := NewSQLHistory(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistry()
+	r.SetHistoryProvider(h)
+	r.FixIt(1, "a", func(ctx context.Context, version Version, title string) error { return nil })
+
+	// 校验偏差失败时 status 报告错误，而不是静默忽略
+	failed := errors.New("failed")
+	fakeSQL.db(dsn).failWith(func(query string) error {
+		if strings.HasPrefix(query, "SELECT version, steps, fingerprint ") {
+			return failed
+		}
+		return nil
+	})
+	var out bytes.Buffer
+	if err = Command(r, &out)(ctx, []string{"status"}); !errors.Is(err, failed) {
+		t.Errorf("want %v, got %v", failed, err)
+	}
+
+	// 历史记录提供者不支持指纹时 status 正常输出
+	out.Reset()
+	if err = Command(r, &out, WithHistory(&memoryHistory{}))(ctx, []string{"status"}); err != nil {
+		t.Error(err)
+	}
+	if want := "fixed: 0\nlatest: 1\npending: 1 step(s)\n"; out.String() != want {
+		t.Errorf("want %q, got %q", want, out.String())
+	}
+}
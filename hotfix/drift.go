@@ -0,0 +1,225 @@
+package hotfix
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrorFingerprintUnsupported = errors.New("hotfix: history provider does not support fingerprint")
+	ErrorDrift                  = errors.New("hotfix: registered hotfixes drift from applied history")
+)
+
+// Fingerprint is the fingerprint of the ordered steps of an applied version.
+//
+// 已修复版本的有序步骤指纹
+type Fingerprint struct {
+	Steps  []string `json:"steps"`            // 步骤标题
+	Hashes []string `json:"hashes,omitempty"` // 用户提供的步骤内容哈希，与 Steps 一一对应
+	Sum    string   `json:"sum"`              // 指纹摘要
+}
+
+func fingerprint(cs []*call) *Fingerprint {
+	fp := &Fingerprint{}
+	hashed := false
+	for _, c := range cs {
+		fp.Steps = append(fp.Steps, c.title)
+		fp.Hashes = append(fp.Hashes, c.hash)
+		hashed = hashed || c.hash != ""
+	}
+	if !hashed {
+		fp.Hashes = nil
+	}
+	fp.Sum = fp.sum()
+	return fp
+}
+
+func (fp *Fingerprint) sum() string {
+	s := sha256.New()
+	for i, t := range fp.Steps {
+		s.Write([]byte(t))
+		s.Write([]byte{0})
+		if i < len(fp.Hashes) {
+			s.Write([]byte(fp.Hashes[i]))
+		}
+		s.Write([]byte{0})
+	}
+	return hex.EncodeToString(s.Sum(nil))
+}
+
+func (fp *Fingerprint) hash(i int) string {
+	if i < len(fp.Hashes) {
+		return fp.Hashes[i]
+	}
+	return ""
+}
+
+// Fingerprinter is the optional interface of History provider to record the fingerprints of the applied
+// versions, which are verified by Verify.
+//
+// Fingerprinter 是历史记录提供者记录已修复版本指纹的可选接口，Verify 使用这些指纹进行校验
+type Fingerprinter interface {
+	// RecordFingerprint records the fingerprint of the fixed version.
+	//
+	// RecordFingerprint 记录已修复版本的指纹
+	RecordFingerprint(v Version, fp *Fingerprint)
+	// Fingerprints returns the fingerprints of the applied versions.
+	//
+	// Fingerprints 返回已修复版本的指纹
+	Fingerprints() map[Version]*Fingerprint
+}
+
+// DriftKind is the kind of drift.
+//
+// 偏差类型
+type DriftKind string
+
+const (
+	DriftMissing   DriftKind = "missing"   // 已执行的步骤不再注册
+	DriftRenamed   DriftKind = "renamed"   // 已执行的步骤被重命名
+	DriftReordered DriftKind = "reordered" // 已执行的步骤顺序改变
+	DriftAdded     DriftKind = "added"     // 已修复的版本中新增了步骤
+	DriftChanged   DriftKind = "changed"   // 步骤内容哈希改变
+)
+
+// Drift is a difference between the registered steps and the applied history of a version.
+//
+// 注册的修补步骤与已修复版本历史记录之间的偏差
+type Drift struct {
+	Version  Version   `json:"version"`            // 版本号
+	Kind     DriftKind `json:"kind"`               // 偏差类型
+	Index    int       `json:"index"`              // 已执行的步骤顺序，DriftAdded 为注册的步骤顺序，0 开始
+	Title    string    `json:"title"`              // 已执行的步骤标题，DriftRenamed 与 DriftAdded 为注册的步骤标题
+	Previous string    `json:"previous,omitempty"` // 重命名前的标题，仅用于 DriftRenamed
+}
+
+// String returns the printable form of the drift.
+//
+// 返回偏差的可打印形式
+func (d *Drift) String() string {
+	switch d.Kind {
+	case DriftRenamed:
+		return fmt.Sprintf("[v%d] %d. %s: %q => %q", d.Version, d.Index+1, d.Kind, d.Previous, d.Title)
+	default:
+		return fmt.Sprintf("[v%d] %d. %s: %q", d.Version, d.Index+1, d.Kind, d.Title)
+	}
+}
+
+// Drifts is the list of drifts ordered by version.
+//
+// 按版本排列的偏差列表
+type Drifts []*Drift
+
+// String returns the printable form of the drifts, one drift per line.
+//
+// 返回可打印形式，每行一个偏差
+func (ds Drifts) String() string {
+	if len(ds) == 0 {
+		return "no drift"
+	}
+	var b strings.Builder
+	for i, d := range ds {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(d.String())
+	}
+	return b.String()
+}
+
+// occurrence is a step identified by its title and the number of previous steps with the same title,
+// so that the steps with duplicate titles are matched in order.
+type occurrence struct {
+	title string
+	n     int
+}
+
+func occurrences(steps []string) []occurrence {
+	seen := make(map[string]int, len(steps))
+	result := make([]occurrence, len(steps))
+	for i, t := range steps {
+		result[i] = occurrence{title: t, n: seen[t]}
+		seen[t]++
+	}
+	return result
+}
+
+// compare returns the drifts between the applied fingerprint and the registered one, the steps are
+// matched by (title, occurrence), the content hashes of the matched steps are always compared.
+func compare(v Version, applied, registered *Fingerprint) (ds Drifts) {
+	if applied.Sum == registered.Sum {
+		return
+	}
+	as, rs := occurrences(applied.Steps), occurrences(registered.Steps)
+	index := make(map[occurrence]int, len(rs))
+	for j, o := range rs {
+		index[o] = j
+	}
+	used := make([]bool, len(rs))
+	for i, o := range as {
+		j, ok := index[o]
+		switch {
+		case !ok && i < len(rs) && !slices.Contains(as, rs[i]):
+			// 同一位置的注册步骤未曾执行过，视为重命名
+			used[i] = true
+			ds = append(ds, &Drift{Version: v, Kind: DriftRenamed, Index: i, Title: rs[i].title, Previous: o.title})
+		case !ok:
+			ds = append(ds, &Drift{Version: v, Kind: DriftMissing, Index: i, Title: o.title})
+		default:
+			used[j] = true
+			if j != i {
+				ds = append(ds, &Drift{Version: v, Kind: DriftReordered, Index: i, Title: o.title})
+			}
+			if h := applied.hash(i); h != "" && h != registered.hash(j) {
+				ds = append(ds, &Drift{Version: v, Kind: DriftChanged, Index: i, Title: o.title})
+			}
+		}
+	}
+	for j, o := range rs {
+		if !used[j] {
+			ds = append(ds, &Drift{Version: v, Kind: DriftAdded, Index: j, Title: o.title})
+		}
+	}
+	return
+}
+
+// Verify compares the fingerprints of the applied versions with the registered steps, and returns the
+// missing, renamed, reordered, added or changed steps. The history provider must implement Fingerprinter.
+//
+// 比较已修复版本的指纹与注册的修补步骤，返回缺失、重命名、顺序改变、新增或内容改变的步骤，
+// 历史记录提供者必须实现 Fingerprinter 接口。
+func (r *Registry) Verify(ctx context.Context, opts ...Option) (Drifts, error) {
+	return r.verify(r.options(opts))
+}
+
+func (r *Registry) verify(o *options) (ds Drifts, err error) {
+	if o.history == nil {
+		return nil, ErrorHistoryUndefined
+	}
+	f, ok := o.history.(Fingerprinter)
+	if !ok {
+		return nil, ErrorFingerprintUnsupported
+	}
+	fps := f.Fingerprints()
+	if err = historyErr(o.history); err != nil {
+		return nil, err
+	}
+	versions := make([]Version, 0, len(fps))
+	for v := range fps {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, v := range versions {
+		ds = append(ds, compare(v, fps[v], fingerprint(r.calls[v]))...)
+	}
+	return
+}
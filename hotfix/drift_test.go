@@ -0,0 +1,101 @@
+package hotfix
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	h, err := OpenFileHistory(filepath.Join(t.TempDir(), "hotfix.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx := context.Background()
+	nop := func(ctx context.Context, version Version, title string) error { return nil }
+	applied := NewRegistry()
+	applied.SetHistoryProvider(h)
+	applied.FixIt(1, "a", nop)
+	applied.FixIt(1, "b", nop)
+	applied.FixIt(1, "c", nop)
+	applied.FixIt(2, "x", nop)
+	applied.FixIt(2, "y", nop)
+	applied.FixIt(3, "z", nop, Hash("1"))
+	if err = applied.Patch(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if ds, err := applied.Verify(ctx); err != nil || len(ds) > 0 {
+		t.Fatalf("want no drift, got %v (%v)", ds, err)
+	}
+
+	edited := NewRegistry()
+	edited.SetHistoryProvider(h)
+	edited.FixIt(1, "a", nop)
+	edited.FixIt(1, "B", nop)
+	edited.FixIt(1, "c", nop)
+	edited.FixIt(2, "y", nop)
+	edited.FixIt(2, "x", nop)
+	edited.FixIt(3, "z", nop, Hash("2"))
+	edited.FixIt(3, "w", nop)
+	edited.FixIt(4, "new", nop)
+	ds, err := edited.Verify(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[v1] 2. renamed: "b" => "B"
+[v2] 1. reordered: "x"
+[v2] 2. reordered: "y"
+[v3] 1. changed: "z"
+[v3] 2. added: "w"`
+	if got := ds.String(); got != want {
+		t.Errorf("want\n%s\ngot\n%s", want, got)
+	}
+	if err = edited.Patch(ctx, 1, Strict()); !errors.Is(err, ErrorDrift) {
+		t.Errorf("want %v, got %v", ErrorDrift, err)
+	}
+	if v := h.Fixed(); v != 3 {
+		t.Errorf("strict patch must not run, got fixed version %d", v)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	print := func(steps []string, hashes ...string) *Fingerprint {
+		fp := &Fingerprint{Steps: steps, Hashes: hashes}
+		fp.Sum = fp.sum()
+		return fp
+	}
+	cases := []struct {
+		name                string
+		applied, registered *Fingerprint
+		want                string
+	}{
+		{
+			// 同名步骤按出现次序匹配
+			name:       "duplicate titles",
+			applied:    print([]string{"a", "a", "b"}),
+			registered: print([]string{"a", "a", "b", "c"}),
+			want:       `[v1] 4. added: "c"`,
+		},
+		{
+			name:       "duplicate titles reordered",
+			applied:    print([]string{"a", "b", "a"}),
+			registered: print([]string{"a", "a", "b"}),
+			want:       "[v1] 2. reordered: \"b\"\n[v1] 3. reordered: \"a\"",
+		},
+		{
+			// 顺序改变的步骤同样比较内容哈希
+			name:       "reordered and changed",
+			applied:    print([]string{"p", "q"}, "1", "2"),
+			registered: print([]string{"q", "p"}, "2", "3"),
+			want:       "[v1] 1. reordered: \"p\"\n[v1] 1. changed: \"p\"\n[v1] 2. reordered: \"q\"",
+		},
+	}
+	for _, c := range cases {
+		if got := compare(1, c.applied, c.registered).String(); got != c.want {
+			t.Errorf("%s: want\n%s\ngot\n%s", c.name, c.want, got)
+		}
+	}
+}
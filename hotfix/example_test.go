@@ -3,6 +3,8 @@ package hotfix_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/keepitlight/golang/hotfix"
 )
@@ -77,44 +79,46 @@ func ExamplePatch() {
 	// 2. Example 6
 }
 
-func ExampleFixIt() {
+func ExampleRegistry_FixIt() {
 	const (
 		V3 hotfix.Version = 3
 		V2 hotfix.Version = 2
 		V1 hotfix.Version = 1
 	)
 
+	r := hotfix.NewRegistry()
+
 	// STEP 1 : define fixes, usually defined in the init function
-	hotfix.FixIt(V1, "Example 1", func(ctx context.Context, version hotfix.Version, title string) error {
+	r.FixIt(V1, "Example 1", func(ctx context.Context, version hotfix.Version, title string) error {
 		fmt.Printf("[v%d] %s fixed\n", version, title)
 		return nil
 	})
-	hotfix.FixIt(V1, "Example 2", func(ctx context.Context, version hotfix.Version, title string) error {
+	r.FixIt(V1, "Example 2", func(ctx context.Context, version hotfix.Version, title string) error {
 		fmt.Printf("[v%d] %s fixed\n", version, title)
 		return nil
 	})
-	hotfix.FixIt(V2, "Example 3", func(ctx context.Context, version hotfix.Version, title string) error {
+	r.FixIt(V2, "Example 3", func(ctx context.Context, version hotfix.Version, title string) error {
 		fmt.Printf("[v%d] %s fixed\n", version, title)
 		return nil
 	})
-	hotfix.FixIt(V2, "Example 4", func(ctx context.Context, version hotfix.Version, title string) error {
+	r.FixIt(V2, "Example 4", func(ctx context.Context, version hotfix.Version, title string) error {
 		fmt.Printf("[v%d] %s fixed\n", version, title)
 		return nil
 	})
-	hotfix.FixIt(V3, "Example 5", func(ctx context.Context, version hotfix.Version, title string) error {
+	r.FixIt(V3, "Example 5", func(ctx context.Context, version hotfix.Version, title string) error {
 		fmt.Printf("[v%d] %s fixed\n", version, title)
 		return nil
 	})
-	hotfix.FixIt(V3, "Example 6", func(ctx context.Context, version hotfix.Version, title string) error {
+	r.FixIt(V3, "Example 6", func(ctx context.Context, version hotfix.Version, title string) error {
 		fmt.Printf("[v%d] %s fixed\n", version, title)
 		return nil
 	})
 
 	// STEP 2 : apply fixes
-	hotfix.SetHistoryProvider(&HistoryWrapper{
+	r.SetHistoryProvider(&HistoryWrapper{
 		fixed: V1,
 	})
-	if err := hotfix.Patch(context.Background(), V1); err != nil {
+	if err := r.Patch(context.Background(), V1); err != nil {
 		panic(err)
 	}
 	// Output:
@@ -123,3 +127,96 @@ func ExampleFixIt() {
 	// [v3] Example 5 fixed
 	// [v3] Example 6 fixed
 }
+
+func ExampleRegistry_Patch() {
+	r := hotfix.NewRegistry()
+	r.FixIt(1, "Create table", func(ctx context.Context, version hotfix.Version, title string) error {
+		return nil
+	})
+	r.FixIt(2, "Fill column", func(ctx context.Context, version hotfix.Version, title string) error {
+		return nil
+	})
+
+	// patch each tenant database with its own history
+	for _, tenant := range []struct {
+		name  string
+		fixed hotfix.Version
+	}{
+		{"alpha", 0},
+		{"beta", 1},
+	} {
+		history := &HistoryWrapper{
+			fixed: tenant.fixed,
+			recorder: func(v hotfix.Version, summary []string) {
+				fmt.Printf("%s [v%d] %v\n", tenant.name, v, summary)
+			},
+		}
+		if err := r.Patch(context.Background(), 1, hotfix.WithHistory(history)); err != nil {
+			panic(err)
+		}
+	}
+	// Output:
+	// alpha [v1] [Create table]
+	// alpha [v2] [Fill column]
+	// beta [v2] [Fill column]
+}
+
+func ExampleCommand() {
+	dir, err := os.MkdirTemp("", "hotfix")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	history, err := hotfix.OpenFileHistory(filepath.Join(dir, "hotfix.journal"))
+	if err != nil {
+		panic(err)
+	}
+	defer history.Close()
+
+	nop := func(ctx context.Context, version hotfix.Version, title string) error { return nil }
+	r := hotfix.NewRegistry()
+	r.SetHistoryProvider(history)
+	r.FixIt(1, "Create table", nop, hotfix.Undo(nop))
+	r.FixIt(2, "Fill column", nop, hotfix.Undo(nop))
+	r.FixIt(3, "Drop column", nop)
+
+	run := hotfix.Command(r, os.Stdout)
+	for _, args := range [][]string{
+		{"plan", "-to", "2"},
+		{"apply", "-to", "2"},
+		{"status"},
+		{"rollback", "-to", "1"},
+		{"status", "-format", "json"},
+	} {
+		if err := run(context.Background(), args); err != nil {
+			panic(err)
+		}
+	}
+	// Output:
+	// [v1] 1. Create table (reversible)
+	// [v2] 1. Fill column (reversible)
+	// [v1] 1. Create table (reversible)
+	// [v2] 1. Fill column (reversible)
+	// fixed: 2
+	// fixed: 2
+	// latest: 3
+	// pending: 1 step(s)
+	// fixed: 1
+	// {
+	//   "fixed": 1,
+	//   "latest": 3,
+	//   "pending": [
+	//     {
+	//       "version": 2,
+	//       "index": 0,
+	//       "title": "Fill column",
+	//       "reversible": true
+	//     },
+	//     {
+	//       "version": 3,
+	//       "index": 0,
+	//       "title": "Drop column"
+	//     }
+	//   ]
+	// }
+}
@@ -0,0 +1,333 @@
+package hotfix
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorJournalCorrupted = errors.New("hotfix: journal corrupted")
+	ErrorJournalClosed    = errors.New("hotfix: journal closed")
+)
+
+var journalTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Action is the action of a journal entry.
+//
+// 日志条目的动作
+type Action string
+
+const (
+	ActionApply  Action = "apply"  // 修复
+	ActionRevert Action = "revert" // 回滚
+	ActionStep   Action = "step"   // 完成版本内的单个步骤
+	ActionPrint  Action = "print"  // 记录已修复版本的指纹
+)
+
+// Entry is a record of the journal of FileHistory.
+//
+// 历史记录日志条目
+type Entry struct {
+	Action   Action    `json:"action,omitempty"`   // 动作，空值视为 ActionApply
+	Version  Version   `json:"version"`            // 版本号
+	Index    int       `json:"index,omitempty"`    // 步骤在版本内的顺序，仅用于 ActionStep
+	Steps    []string  `json:"steps,omitempty"`    // 修补步骤标题
+	Hashes   []string  `json:"hashes,omitempty"`   // 步骤内容哈希，仅用于 ActionPrint
+	Sum      string    `json:"sum,omitempty"`      // 指纹摘要，仅用于 ActionPrint
+	Time     time.Time `json:"time"`               // 记录时间
+	Host     string    `json:"host,omitempty"`     // 主机名
+	Checksum string    `json:"checksum,omitempty"` // 校验和
+}
+
+func (e *Entry) sum() (string, error) {
+	c := *e
+	c.Checksum = ""
+	b, err := json.Marshal(&c)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%08x", crc32.Checksum(b, journalTable)), nil
+}
+
+// FileHistory is a History provider that keeps an append-only journal of JSON lines,
+// every record is synchronized to the disk before returning.
+//
+// 基于文件的历史记录提供者，以 JSON 行格式追加记录日志，每条记录写入后立即同步到磁盘。
+type FileHistory struct {
+	name    string
+	host    string
+	file    *os.File
+	entries []*Entry
+	applied []Version
+	steps   map[Version]int
+	prints  map[Version]*Fingerprint
+	fixed   Version
+	err     error
+	mutex   sync.Mutex
+}
+
+// OpenFileHistory opens or creates the journal file name and loads the applied history. A truncated
+// trailing line left by a crash is discarded, any other damaged line is reported as ErrorJournalCorrupted.
+//
+// 打开或创建日志文件并加载历史记录，崩溃遗留的不完整尾行将被丢弃，其它损坏的行返回 ErrorJournalCorrupted 错误。
+func OpenFileHistory(name string) (*FileHistory, error) {
+	_, statErr := os.Stat(name)
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	h := &FileHistory{name: name, file: f}
+	h.host, _ = os.Hostname()
+	if err = h.load(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if errors.Is(statErr, os.ErrNotExist) {
+		// 新建文件时同步目录，保证文件本身在崩溃后依然存在
+		syncDir(filepath.Dir(name))
+	}
+	return h, nil
+}
+
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
+
+func (h *FileHistory) load() error {
+	if _, err := h.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	var (
+		r      = bufio.NewReader(h.file)
+		offset int64
+		line   int
+	)
+	for {
+		b, err := r.ReadBytes('\n')
+		if len(b) > 0 && b[len(b)-1] != '\n' {
+			// 未写完整的尾行，丢弃
+			return h.file.Truncate(offset)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line++
+		offset += int64(len(b))
+		if b = bytes.TrimSpace(b); len(b) == 0 {
+			continue
+		}
+		e := &Entry{}
+		if err = json.Unmarshal(b, e); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrorJournalCorrupted, line, err)
+		}
+		if s, err := e.sum(); err != nil || s != e.Checksum {
+			return fmt.Errorf("%w: line %d: checksum mismatch", ErrorJournalCorrupted, line)
+		}
+		h.replay(e)
+	}
+}
+
+func (h *FileHistory) replay(e *Entry) {
+	h.entries = append(h.entries, e)
+	if h.prints == nil {
+		h.prints = make(map[Version]*Fingerprint)
+	}
+	switch e.Action {
+	case ActionPrint:
+		h.prints[e.Version] = &Fingerprint{Steps: e.Steps, Hashes: e.Hashes, Sum: e.Sum}
+	case ActionStep:
+		if h.steps == nil {
+			h.steps = make(map[Version]int)
+		}
+		h.steps[e.Version] = e.Index + 1
+	case ActionRevert:
+		delete(h.steps, e.Version)
+		delete(h.prints, e.Version)
+		h.applied = slices.DeleteFunc(h.applied, func(v Version) bool { return v == e.Version })
+		h.fixed = Omitted
+		if len(h.applied) > 0 {
+			h.fixed = slices.Max(h.applied)
+		}
+	default:
+		// 在指纹记录之前，使用步骤标题作为指纹
+		fp := &Fingerprint{Steps: e.Steps}
+		fp.Sum = fp.sum()
+		h.prints[e.Version] = fp
+		delete(h.steps, e.Version)
+		h.applied = append(h.applied, e.Version)
+		h.fixed = e.Version
+	}
+}
+
+func (h *FileHistory) append(e *Entry) error {
+	if h.file == nil {
+		return ErrorJournalClosed
+	}
+	e.Time = time.Now().UTC()
+	e.Host = h.host
+	s, err := e.sum()
+	if err != nil {
+		return err
+	}
+	e.Checksum = s
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err = h.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if err = h.file.Sync(); err != nil {
+		return err
+	}
+	h.replay(e)
+	return nil
+}
+
+// Name returns the name of the journal file.
+//
+// 返回日志文件名
+func (h *FileHistory) Name() string {
+	return h.name
+}
+
+// Fixed returns the last fixed version.
+//
+// 返回最近一次修复的版本号
+func (h *FileHistory) Fixed() Version {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.fixed
+}
+
+// Record appends the fixed version to the journal, the error can be retrieved by Err.
+//
+// 将修复的版本号追加到日志，写入错误可通过 Err 获取
+func (h *FileHistory) Record(v Version, summary []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.append(&Entry{Action: ActionApply, Version: v, Steps: summary}); err != nil && h.err == nil {
+		h.err = err
+	}
+}
+
+// Revert appends the rolled back version to the journal, the error can be retrieved by Err.
+//
+// 将回滚的版本号追加到日志，写入错误可通过 Err 获取
+func (h *FileHistory) Revert(v Version, summary []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.append(&Entry{Action: ActionRevert, Version: v, Steps: summary}); err != nil && h.err == nil {
+		h.err = err
+	}
+}
+
+// Completed returns the number of completed steps of the version which has not been recorded as fixed.
+//
+// 返回尚未记录为已修复的版本中已完成的步骤数
+func (h *FileHistory) Completed(v Version) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.steps[v]
+}
+
+// RecordStep appends the completed step to the journal, the error can be retrieved by Err.
+//
+// 将已完成的步骤追加到日志，写入错误可通过 Err 获取
+func (h *FileHistory) RecordStep(v Version, index int, title string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.append(&Entry{Action: ActionStep, Version: v, Index: index, Steps: []string{title}}); err != nil && h.err == nil {
+		h.err = err
+	}
+}
+
+// RecordFingerprint appends the fingerprint of the fixed version to the journal, the error can be retrieved by Err.
+//
+// 将已修复版本的指纹追加到日志，写入错误可通过 Err 获取
+func (h *FileHistory) RecordFingerprint(v Version, fp *Fingerprint) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.append(&Entry{Action: ActionPrint, Version: v, Steps: fp.Steps, Hashes: fp.Hashes, Sum: fp.Sum}); err != nil && h.err == nil {
+		h.err = err
+	}
+}
+
+// Fingerprints returns the fingerprints of the applied versions.
+//
+// 返回已修复版本的指纹
+func (h *FileHistory) Fingerprints() map[Version]*Fingerprint {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	result := make(map[Version]*Fingerprint, len(h.prints))
+	for v, fp := range h.prints {
+		c := *fp
+		result[v] = &c
+	}
+	return result
+}
+
+// Entries returns a copy of all entries of the journal in the order they were recorded.
+//
+// 按记录顺序返回日志的全部条目（副本）
+func (h *FileHistory) Entries() []Entry {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	result := make([]Entry, 0, len(h.entries))
+	for _, e := range h.entries {
+		c := *e
+		c.Steps = slices.Clone(e.Steps)
+		c.Hashes = slices.Clone(e.Hashes)
+		result = append(result, c)
+	}
+	return result
+}
+
+// Err returns the first error occurred while recording.
+//
+// 返回记录时发生的第一个错误
+func (h *FileHistory) Err() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.err
+}
+
+// Close closes the journal file.
+//
+// 关闭日志文件
+func (h *FileHistory) Close() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+	err := h.file.Close()
+	h.file = nil
+	return err
+}
@@ -0,0 +1,85 @@
+package hotfix
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFileHistory(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hotfix.journal")
+	h, err := OpenFileHistory(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := h.Fixed(); v != Omitted {
+		t.Errorf("empty journal: want %d, got %d", Omitted, v)
+	}
+	h.Record(1, []string{"a", "b"})
+	h.Record(2, []string{"c"})
+	if err = h.Err(); err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Close()
+
+	// 模拟崩溃时写入的不完整尾行
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"version":3,"steps":["d"`)
+	_ = f.Close()
+
+	if h, err = OpenFileHistory(name); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if v := h.Fixed(); v != 2 {
+		t.Errorf("reopened journal: want %d, got %d", 2, v)
+	}
+	es := h.Entries()
+	if len(es) != 2 || !slices.Equal(es[0].Steps, []string{"a", "b"}) || es[1].Version != 2 {
+		t.Errorf("unexpected entries %+v", es)
+	}
+	h.Record(3, []string{"d"})
+	if v := h.Fixed(); v != 3 || h.Err() != nil {
+		t.Errorf("append after truncation: want %d, got %d (%v)", 3, v, h.Err())
+	}
+}
+
+func TestFileHistoryCorrupted(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hotfix.journal")
+	if err := os.WriteFile(name, []byte(`{"version":1,"checksum":"00000000"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileHistory(name); !errors.Is(err, ErrorJournalCorrupted) {
+		t.Errorf("want %v, got %v", ErrorJournalCorrupted, err)
+	}
+}
+
+func TestFileHistoryError(t *testing.T) {
+	h, err := OpenFileHistory(filepath.Join(t.TempDir(), "hotfix.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace []string
+	step := func(ctx context.Context, version Version, title string) error {
+		trace = append(trace, title)
+		return nil
+	}
+	r := &Registry{history: h}
+	r.FixIt(1, "a", step)
+	r.FixIt(1, "b", step)
+
+	// 日志无法写入，记录第一个步骤的进度失败后中止
+	_ = h.Close()
+	if err = r.Patch(context.Background(), 1); err == nil || !errors.Is(err, h.Err()) {
+		t.Errorf("want the journal error, got %v", err)
+	}
+	if !slices.Equal(trace, []string{"a"}) {
+		t.Errorf("want only step a run, got %v", trace)
+	}
+}
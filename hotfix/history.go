@@ -1,5 +1,10 @@
 package hotfix
 
+import (
+	"context"
+	"fmt"
+)
+
 // History is the history provider for hotfix.
 //
 // History 是补丁程序执行的历史记录提供者接口
@@ -13,3 +18,63 @@ type History interface {
 	// Record 记录修复的版本号
 	Record(v Version, summary []string)
 }
+
+// Reverter is the optional interface of History provider to support Rollback.
+//
+// Reverter 是历史记录提供者支持回滚的可选接口
+type Reverter interface {
+	// Revert records the rolled back version, after that Fixed returns the previous fixed version.
+	//
+	// Revert 记录已回滚的版本号，之后 Fixed 返回之前修复的版本号
+	Revert(v Version, summary []string)
+}
+
+// Progress is the optional interface of History provider to record the completed steps, so that Patch
+// resumes from the first incomplete step of a version after a partial failure.
+//
+// Progress 是历史记录提供者记录已完成步骤的可选接口，部分失败后 Patch 从版本内第一个未完成的步骤继续执行
+type Progress interface {
+	// Completed returns the number of completed steps of the version which has not been recorded as fixed.
+	//
+	// Completed 返回尚未记录为已修复的版本中已完成的步骤数
+	Completed(v Version) int
+	// RecordStep records the completed step, index is the 0 start order of the step in the version.
+	//
+	// RecordStep 记录已完成的步骤，index 为步骤在版本内 0 开始的顺序
+	RecordStep(v Version, index int, title string)
+}
+
+// Transactional is the optional interface of History provider to run each step and the record of its
+// progress in one transaction.
+//
+// Transactional 是历史记录提供者的可选接口，在同一事务中执行每个修补步骤并记录其进度
+type Transactional interface {
+	// Begin starts a transaction and returns the context carrying it, end commits the transaction if err
+	// is nil, otherwise rolls it back, and returns the error of the step or the commit.
+	//
+	// Begin 开始事务并返回携带事务的 ctx，end 在 err 为 nil 时提交事务，否则回滚事务，返回步骤或提交的错误
+	Begin(ctx context.Context) (tx context.Context, end func(err error) error, err error)
+}
+
+// ErrorReporter is the optional interface of History provider to report the errors occurred while
+// querying or recording. Patch, Plan, Rollback and Verify stop once Err returns an error, instead of
+// acting on a wrong fixed version or losing the recorded progress.
+//
+// ErrorReporter 是历史记录提供者报告查询或记录错误的可选接口。Err 返回错误后 Patch、Plan、Rollback 与 Verify 立即中止，
+// 避免基于错误的已修复版本执行或丢失已记录的进度
+type ErrorReporter interface {
+	// Err returns the first error occurred while querying or recording.
+	//
+	// Err 返回查询或记录时发生的第一个错误
+	Err() error
+}
+
+// historyErr returns the error of the history provider if it implements ErrorReporter.
+func historyErr(history History) error {
+	if r, ok := history.(ErrorReporter); ok {
+		if err := r.Err(); err != nil {
+			return fmt.Errorf("hotfix: history: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package hotfix
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Event is the lifecycle event of a step.
+//
+// 修补步骤的生命周期事件
+type Event struct {
+	Version  Version       // 版本号
+	Title    string        // 标题
+	Attempt  int           // 尝试次数，1 开始
+	Duration time.Duration // 执行耗时，仅用于 OnSuccess 与 OnFailure
+	Err      error         // 错误，仅用于 OnFailure
+}
+
+// Hooks are the lifecycle hooks of steps, any of them may be nil.
+// OnFailure is called for every failed attempt, OnSkip is called for the step completed by a previous run.
+//
+// 修补步骤的生命周期钩子，均可为 nil。每次尝试失败时都会调用 OnFailure，之前已完成的步骤调用 OnSkip
+type Hooks struct {
+	OnStart   func(ctx context.Context, e *Event)
+	OnSuccess func(ctx context.Context, e *Event)
+	OnFailure func(ctx context.Context, e *Event)
+	OnSkip    func(ctx context.Context, e *Event)
+}
+
+func (h *Hooks) start(ctx context.Context, e *Event) {
+	if h != nil && h.OnStart != nil {
+		h.OnStart(ctx, e)
+	}
+}
+
+func (h *Hooks) success(ctx context.Context, e *Event) {
+	if h != nil && h.OnSuccess != nil {
+		h.OnSuccess(ctx, e)
+	}
+}
+
+func (h *Hooks) failure(ctx context.Context, e *Event) {
+	if h != nil && h.OnFailure != nil {
+		h.OnFailure(ctx, e)
+	}
+}
+
+func (h *Hooks) skip(ctx context.Context, e *Event) {
+	if h != nil && h.OnSkip != nil {
+		h.OnSkip(ctx, e)
+	}
+}
+
+// LogHooks returns the hooks which log the events through logger, nil uses slog.Default().
+//
+// 返回通过 logger 记录事件的钩子，logger 为 nil 时使用 slog.Default()
+func LogHooks(logger *slog.Logger) *Hooks {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	attrs := func(e *Event) []slog.Attr {
+		return []slog.Attr{
+			slog.Int("version", int(e.Version)),
+			slog.String("title", e.Title),
+			slog.Int("attempt", e.Attempt),
+		}
+	}
+	return &Hooks{
+		OnStart: func(ctx context.Context, e *Event) {
+			logger.LogAttrs(ctx, slog.LevelInfo, "hotfix start", attrs(e)...)
+		},
+		OnSuccess: func(ctx context.Context, e *Event) {
+			logger.LogAttrs(ctx, slog.LevelInfo, "hotfix success", append(attrs(e), slog.Duration("duration", e.Duration))...)
+		},
+		OnFailure: func(ctx context.Context, e *Event) {
+			logger.LogAttrs(ctx, slog.LevelError, "hotfix failure", append(attrs(e), slog.Duration("duration", e.Duration), slog.Any("error", e.Err))...)
+		},
+		OnSkip: func(ctx context.Context, e *Event) {
+			logger.LogAttrs(ctx, slog.LevelInfo, "hotfix skip", attrs(e)...)
+		},
+	}
+}
+
+// Retry is the retry policy of the steps failed with the error marked by Retryable.
+//
+// 重试策略，仅用于使用 Retryable 标记的错误
+type Retry struct {
+	Attempts   int           // 最大尝试次数（含首次），小于 2 时不重试
+	Backoff    time.Duration // 首次重试前的等待时间，之后每次加倍
+	MaxBackoff time.Duration // 等待时间上限，0 不限制
+}
+
+func (r *Retry) attempts() int {
+	if r == nil || r.Attempts < 1 {
+		return 1
+	}
+	return r.Attempts
+}
+
+func (r *Retry) delay(attempt int) time.Duration {
+	d := r.Backoff
+	for i := 1; i < attempt; i++ {
+		if r.MaxBackoff > 0 && d >= r.MaxBackoff {
+			break
+		}
+		d *= 2
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	return d
+}
+
+type retryable struct {
+	err error
+}
+
+func (e *retryable) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryable) Unwrap() error {
+	return e.err
+}
+
+// Retryable marks the error as retryable, nil returns nil.
+//
+// 将错误标记为可重试，err 为 nil 时返回 nil
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryable{err: err}
+}
+
+// IsRetryable reports whether the error is marked by Retryable.
+//
+// 检查错误是否已被标记为可重试
+func IsRetryable(err error) bool {
+	var r *retryable
+	return errors.As(err, &r)
+}
+
+// run calls the hotfix of the step with timeout, retry policy and hooks, done is called after the
+// hotfix succeeded, within the transaction if the history provider implements Transactional.
+func (o *options) run(ctx context.Context, v Version, c *call, done func()) error {
+	timeout, retry := o.timeout, o.retry
+	if c.timeout > 0 {
+		timeout = c.timeout
+	}
+	if c.retry != nil {
+		retry = c.retry
+	}
+	attempts := retry.attempts()
+	for attempt := 1; ; attempt++ {
+		e := &Event{Version: v, Title: c.title, Attempt: attempt}
+		o.hooks.start(ctx, e)
+		begin := time.Now()
+		err := o.invoke(ctx, timeout, v, c, done)
+		e.Duration = time.Since(begin)
+		if err == nil {
+			o.hooks.success(ctx, e)
+			return nil
+		}
+		e.Err = err
+		o.hooks.failure(ctx, e)
+		if attempt >= attempts || !IsRetryable(err) {
+			return err
+		}
+		t := time.NewTimer(retry.delay(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+func (o *options) invoke(ctx context.Context, timeout time.Duration, v Version, c *call, done func()) (err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if t, ok := o.history.(Transactional); ok {
+		var end func(err error) error
+		if ctx, end, err = t.Begin(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			err = end(err)
+		}()
+	}
+	if err = c.hotfix(ctx, v, c.title); err == nil && done != nil {
+		done()
+	}
+	return
+}
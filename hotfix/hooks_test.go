@@ -0,0 +1,68 @@
+package hotfix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPatchHooks(t *testing.T) {
+	var (
+		trace    []string
+		failures = 2
+	)
+	event := func(name string) func(ctx context.Context, e *Event) {
+		return func(ctx context.Context, e *Event) {
+			trace = append(trace, fmt.Sprintf("%s %s #%d", name, e.Title, e.Attempt))
+		}
+	}
+	hooks := &Hooks{
+		OnStart:   event("start"),
+		OnSuccess: event("success"),
+		OnFailure: event("failure"),
+		OnSkip:    event("skip"),
+	}
+
+	r := NewRegistry()
+	r.FixIt(1, "flaky", func(ctx context.Context, version Version, title string) error {
+		if failures > 0 {
+			failures--
+			return Retryable(errors.New("busy"))
+		}
+		return nil
+	})
+	r.FixIt(1, "slow", func(ctx context.Context, version Version, title string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, Timeout(10*time.Millisecond))
+
+	err := r.Patch(context.Background(), 1,
+		WithHistory(&memoryHistory{}),
+		WithHooks(hooks),
+		StepRetry(&Retry{Attempts: 3, Backoff: time.Millisecond}),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	want := []string{
+		"start flaky #1", "failure flaky #1",
+		"start flaky #2", "failure flaky #2",
+		"start flaky #3", "success flaky #3",
+		"start slow #1", "failure slow #1",
+	}
+	if !slices.Equal(trace, want) {
+		t.Errorf("want %v, got %v", want, trace)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	r := &Retry{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for i, want := range []time.Duration{10, 20, 40, 50, 50} {
+		if got := r.delay(i + 1); got != want*time.Millisecond {
+			t.Errorf("%d. want %v, got %v", i+1, want*time.Millisecond, got)
+		}
+	}
+}
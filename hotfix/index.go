@@ -2,31 +2,80 @@ package hotfix
 
 import (
 	"context"
+	"sync"
 )
 
 var (
-	hotfixes = &fixes{}
+	hotfixes = NewRegistry()
+	once     sync.Once
 )
 
+// Default returns the default registry used by the package-level functions.
+//
+// 返回包级函数使用的默认注册表
+func Default() *Registry {
+	return hotfixes
+}
+
 // SetHistoryProvider to set the history provider.
 //
 // 设置历史记录接口
 func SetHistoryProvider(history History) {
-	hotfixes.history = history
+	hotfixes.SetHistoryProvider(history)
+}
+
+// SetLocker to set the cross-process locker acquired by Patch and Rollback.
+//
+// 设置 Patch 与 Rollback 使用的跨进程锁
+func SetLocker(locker Locker) {
+	hotfixes.SetLocker(locker)
 }
 
 // Patch to run hotfixes if and only if Do is being called for the first time.
 // Argument version indicates the version from which to start patching.
+// If the history provider implements Progress, every completed step is recorded and a partially
+// patched version resumes from its first incomplete step.
+// Use Registry to patch many times, e.g. once per tenant.
 //
 // 如果是第一次调用 Do，则运行热修补。 参数 version 指示从指定的版本开始执行修补。
-func Patch(ctx context.Context, version Version) error {
-	return hotfixes.do(ctx, version)
+// 如果历史记录提供者实现了 Progress 接口，则记录每个已完成的步骤，部分修补的版本从第一个未完成的步骤继续执行。
+// 需要多次修补（例如为每个租户分别修补）时使用 Registry。
+func Patch(ctx context.Context, version Version, opts ...Option) (err error) {
+	once.Do(func() {
+		err = hotfixes.Patch(ctx, version, opts...)
+	})
+	return
 }
 
 // FixIt to register a hotfix function, which will be executed when the version is reached.
 // version must be greater than 0
 //
 // 注册热修补函数
-func FixIt(version Version, title string, hotfix Hotfix) {
-	hotfixes.append(version, title, hotfix)
+func FixIt(version Version, title string, hotfix Hotfix, opts ...FixOption) {
+	hotfixes.FixIt(version, title, hotfix, opts...)
+}
+
+// Rollback to run the undo functions in reverse order, version by version and step by step, until the
+// fixed version drops back to target. The history provider must implement Reverter. Steps without undo
+// function block the rollback with ErrorIrreversible unless Force is given.
+//
+// 回滚到目标版本，按版本和步骤的逆序执行撤销函数，历史记录提供者必须实现 Reverter 接口。
+// 存在没有撤销函数的步骤时返回 ErrorIrreversible 错误，除非指定了 Force 选项。
+func Rollback(ctx context.Context, target Version, opts ...Option) error {
+	return hotfixes.Rollback(ctx, target, opts...)
+}
+
+// Plan returns the pending steps in execution order without executing them, argument version
+// indicates the version from which to start patching, same as Patch.
+//
+// 返回待执行的修补步骤（按执行顺序），但不执行。参数 version 与 Patch 相同，指示从指定的版本开始执行修补。
+func Plan(ctx context.Context, version Version, opts ...Option) (Steps, error) {
+	return hotfixes.Plan(ctx, version, opts...)
+}
+
+// Verify compares the fingerprints of the applied versions with the registered steps, see Registry.Verify.
+//
+// 比较已修复版本的指纹与注册的修补步骤，参见 Registry.Verify
+func Verify(ctx context.Context, opts ...Option) (Drifts, error) {
+	return hotfixes.Verify(ctx, opts...)
 }
@@ -0,0 +1,230 @@
+package hotfix
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorLockHeld        = errors.New("hotfix: lock already held")
+	ErrorLockNotHeld     = errors.New("hotfix: lock not held")
+	ErrorLockUnsupported = errors.New("hotfix: file lock unsupported on this platform")
+)
+
+// Locker is the cross-process lock acquired by Patch before reading the fixed version and released
+// after recording, so that only one replica applies the hotfixes. A lock of a crashed holder should be
+// released after a lease timeout.
+//
+// 跨进程锁，Patch 在读取已修复版本前获取，记录完成后释放，保证只有一个副本执行修补。崩溃的持有者的锁应在租期超时后释放
+type Locker interface {
+	// Lock acquires the lock, blocking until it is acquired or the context is done.
+	//
+	// Lock 获取锁，阻塞直到获取成功或 ctx 结束
+	Lock(ctx context.Context) error
+	// Unlock releases the lock.
+	//
+	// Unlock 释放锁
+	Unlock() error
+}
+
+const (
+	DefaultLease = 30 * time.Second // 默认租期
+)
+
+// FileLocker is an advisory lock based on a lease recorded in a lock file. The lease is read and
+// written under flock(2) of the file, so that acquiring, renewing and releasing are atomic across
+// processes. The holder renews its own lease periodically, the lease of a crashed holder expires
+// and can be taken over. The lock file is never removed. FileLocker returns ErrorLockUnsupported
+// on the platforms without flock.
+//
+// 基于锁文件中租约的建议锁。租约在锁文件的 flock(2) 保护下读写，因此获取、续租与释放在进程间都是原子的。
+// 持有者定期续租自己的租约，崩溃的持有者的租约过期后可被接管。锁文件不会被删除。不支持 flock 的平台返回 ErrorLockUnsupported。
+type FileLocker struct {
+	name  string
+	lease time.Duration
+	token string
+	stop  chan struct{}
+	done  chan struct{}
+	mutex sync.Mutex
+}
+
+// NewFileLocker creates a lock on the file name, lease less than 1 uses DefaultLease.
+// The lock is not reentrant, Lock returns ErrorLockHeld if the FileLocker holds the lock already.
+//
+// 创建基于文件 name 的锁，lease 小于 1 时使用 DefaultLease。锁不可重入，FileLocker 已持有锁时 Lock 返回 ErrorLockHeld
+func NewFileLocker(name string, lease time.Duration) *FileLocker {
+	if lease < 1 {
+		lease = DefaultLease
+	}
+	return &FileLocker{name: name, lease: lease}
+}
+
+func (l *FileLocker) interval() time.Duration {
+	return max(min(l.lease/10, 100*time.Millisecond), time.Millisecond)
+}
+
+// leaseRecord is the content of the lock file, the host and pid are only for diagnosis.
+type leaseRecord struct {
+	token   string
+	expires time.Time
+}
+
+func (r *leaseRecord) String() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s %d %s %d\n", r.token, r.expires.UnixNano(), host, os.Getpid())
+}
+
+// update calls f with the lease record of the lock file under flock, writes the record back if f returns true.
+func (l *FileLocker) update(f func(r *leaseRecord) bool) error {
+	file, err := os.OpenFile(l.name, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = lockFile(file); err != nil {
+		return err
+	}
+	defer unlockFile(file)
+
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	r := &leaseRecord{}
+	var expires int64
+	// 空文件或无法解析的内容视为没有租约
+	if fields := strings.Fields(string(b)); len(fields) >= 2 {
+		if _, e := fmt.Sscan(fields[1], &expires); e == nil {
+			r.token, r.expires = fields[0], time.Unix(0, expires)
+		}
+	}
+	if !f(r) {
+		return nil
+	}
+	if err = file.Truncate(0); err != nil {
+		return err
+	}
+	if r.token != "" {
+		// 释放的租约保留为空文件
+		if _, err = file.WriteAt([]byte(r.String()), 0); err != nil {
+			return err
+		}
+	}
+	return file.Sync()
+}
+
+// Lock acquires the lock, blocking until it is acquired or the context is done.
+//
+// 获取锁，阻塞直到获取成功或 ctx 结束
+func (l *FileLocker) Lock(ctx context.Context) error {
+	l.mutex.Lock()
+	held := l.token != ""
+	l.mutex.Unlock()
+	if held {
+		return ErrorLockHeld
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(b)
+	for {
+		acquired := false
+		err := l.update(func(r *leaseRecord) bool {
+			now := time.Now()
+			if r.token != "" && now.Before(r.expires) {
+				return false
+			}
+			// 没有租约、已释放或崩溃的持有者租约已过期
+			r.token, r.expires = token, now.Add(l.lease)
+			acquired = true
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if acquired {
+			l.mutex.Lock()
+			l.token = token
+			l.stop = make(chan struct{})
+			l.done = make(chan struct{})
+			go l.renew(token, l.stop, l.done)
+			l.mutex.Unlock()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("hotfix: lock %s: %w", l.name, ctx.Err())
+		case <-time.After(l.interval()):
+		}
+	}
+}
+
+// renew extends the lease of token periodically, stops if the lease has been taken over by another holder.
+func (l *FileLocker) renew(token string, stop, done chan struct{}) {
+	defer close(done)
+
+	t := time.NewTicker(max(l.lease/3, time.Millisecond))
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			lost := false
+			_ = l.update(func(r *leaseRecord) bool {
+				if r.token != token {
+					// 租约已被接管，不能延长其它持有者的租约
+					lost = true
+					return false
+				}
+				r.expires = time.Now().Add(l.lease)
+				return true
+			})
+			if lost {
+				return
+			}
+		}
+	}
+}
+
+// Unlock releases the lock, returns ErrorLockNotHeld if the lock is not held or has been taken over
+// by another holder after the lease expired.
+//
+// 释放锁，如果未持有锁或租期过后锁已被其它持有者接管，则返回 ErrorLockNotHeld
+func (l *FileLocker) Unlock() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.token == "" {
+		return ErrorLockNotHeld
+	}
+	close(l.stop)
+	<-l.done
+	token := l.token
+	l.token, l.stop, l.done = "", nil, nil
+
+	held := false
+	err := l.update(func(r *leaseRecord) bool {
+		if held = r.token == token; !held {
+			return false
+		}
+		r.token = ""
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if !held {
+		return ErrorLockNotHeld
+	}
+	return nil
+}
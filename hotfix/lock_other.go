@@ -0,0 +1,13 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package hotfix
+
+import "os"
+
+func lockFile(f *os.File) error {
+	return ErrorLockUnsupported
+}
+
+func unlockFile(f *os.File) error {
+	return ErrorLockUnsupported
+}
@@ -0,0 +1,130 @@
+package hotfix
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileLocker(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hotfix.lock")
+	a := NewFileLocker(name, time.Second)
+	b := NewFileLocker(name, time.Second)
+	if err := a.Lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Lock(context.Background()); !errors.Is(err, ErrorLockHeld) {
+		t.Errorf("want %v, got %v", ErrorLockHeld, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.Lock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if err := a.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Unlock(); !errors.Is(err, ErrorLockNotHeld) {
+		t.Errorf("want %v, got %v", ErrorLockNotHeld, err)
+	}
+}
+
+func TestFileLockerExpired(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hotfix.lock")
+	// 之前运行遗留的无效锁文件不影响获取锁
+	if err := os.WriteFile(name, []byte("crashed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lease := 100 * time.Millisecond
+	a := NewFileLocker(name, lease)
+	if err := a.Lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟持有者崩溃：停止续租且不释放锁
+	close(a.stop)
+	<-a.done
+
+	b := NewFileLocker(name, time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := b.Lock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < lease/2 {
+		t.Errorf("lock taken over after %v, before the lease expired", d)
+	}
+	if err := b.Unlock(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFileLockerTakenOver(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hotfix.lock")
+	a := NewFileLocker(name, 30*time.Millisecond)
+	if err := a.Lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 租约被其它持有者接管后，续租不能延长其它持有者的租约
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	other := &FileLocker{name: name}
+	if err := other.update(func(r *leaseRecord) bool {
+		r.token, r.expires = "other", expires
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	_ = other.update(func(r *leaseRecord) bool {
+		if r.token != "other" || !r.expires.Equal(expires) {
+			t.Errorf("lease of the other holder changed: %s %v", r.token, r.expires)
+		}
+		return false
+	})
+	if err := a.Unlock(); !errors.Is(err, ErrorLockNotHeld) {
+		t.Errorf("want %v, got %v", ErrorLockNotHeld, err)
+	}
+}
+
+func TestPatchLocked(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hotfix.lock")
+	history := &memoryHistory{}
+	var (
+		mutex sync.Mutex
+		runs  int
+	)
+	r := NewRegistry()
+	r.FixIt(1, "a", func(ctx context.Context, version Version, title string) error {
+		mutex.Lock()
+		runs++
+		mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 每个副本使用各自的锁实例
+			if err := r.Patch(context.Background(), 1, WithHistory(history), WithLocker(NewFileLocker(name, time.Second))); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if runs != 1 {
+		t.Errorf("want the hotfix run once, got %d", runs)
+	}
+}
@@ -0,0 +1,61 @@
+package hotfix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Step is a pending step of the plan.
+//
+// 待执行的修补步骤
+type Step struct {
+	Version    Version `json:"version"`              // 版本号
+	Index      int     `json:"index"`                // 在版本内的顺序，0 开始
+	Title      string  `json:"title"`                // 标题
+	Reversible bool    `json:"reversible,omitempty"` // 是否可撤销
+}
+
+// String returns the printable form of the step.
+//
+// 返回步骤的可打印形式
+func (s *Step) String() string {
+	r := ""
+	if s.Reversible {
+		r = " (reversible)"
+	}
+	return fmt.Sprintf("[v%d] %d. %s%s", s.Version, s.Index+1, s.Title, r)
+}
+
+// Steps is the ordered list of pending steps.
+//
+// 按执行顺序排列的待执行修补步骤
+type Steps []*Step
+
+// Versions returns the distinct versions of the steps in order.
+//
+// 按顺序返回步骤涉及的版本号（不重复）
+func (ss Steps) Versions() (versions []Version) {
+	for _, s := range ss {
+		if l := len(versions); l == 0 || versions[l-1] != s.Version {
+			versions = append(versions, s.Version)
+		}
+	}
+	return
+}
+
+// String returns the printable form of the steps, one step per line.
+//
+// 返回可打印形式，每行一个步骤
+func (ss Steps) String() string {
+	if len(ss) == 0 {
+		return "no pending hotfix"
+	}
+	var b strings.Builder
+	for i, s := range ss {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
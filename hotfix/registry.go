@@ -0,0 +1,358 @@
+package hotfix
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Registry is a set of registered hotfix functions and its history provider, which can be patched
+// many times, e.g. once per tenant database.
+//
+// 热修补函数注册表，包含注册的热修补函数及其历史记录提供者，可以多次执行修补，例如为每个租户数据库分别修补
+type Registry struct {
+	history History
+	locker  Locker
+	calls   map[Version][]*call
+	mutex   sync.Mutex
+}
+
+// NewRegistry creates an empty registry.
+//
+// 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// SetHistoryProvider to set the history provider.
+//
+// 设置历史记录接口
+func (r *Registry) SetHistoryProvider(history History) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.history = history
+}
+
+// SetLocker to set the cross-process locker acquired by Patch and Rollback.
+//
+// 设置 Patch 与 Rollback 使用的跨进程锁
+func (r *Registry) SetLocker(locker Locker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.locker = locker
+}
+
+// History returns the history provider.
+//
+// 返回历史记录接口
+func (r *Registry) History() History {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.history
+}
+
+// FixIt to register a hotfix function, which will be executed when the version is reached.
+// version must be greater than 0
+//
+// 注册热修补函数
+func (r *Registry) FixIt(version Version, title string, hotfix Hotfix, opts ...FixOption) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.calls == nil {
+		r.calls = make(map[Version][]*call)
+	}
+	c := &call{
+		title:  title,
+		hotfix: hotfix,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	r.calls[version] = append(r.calls[version], c)
+}
+
+// options applies opts, the history provider of the registry is used if none is given.
+func (r *Registry) options(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if o.history == nil {
+		o.history = r.history
+	}
+	if o.locker == nil {
+		o.locker = r.locker
+	}
+	return o
+}
+
+// lock acquires the locker if any, the returned function releases it and joins the error.
+func (o *options) lock(ctx context.Context) (unlock func(err *error), err error) {
+	if o.locker == nil {
+		return func(*error) {}, nil
+	}
+	if err = o.locker.Lock(ctx); err != nil {
+		return nil, err
+	}
+	return func(err *error) {
+		if e := o.locker.Unlock(); e != nil && *err == nil {
+			*err = e
+		}
+	}, nil
+}
+
+// Latest returns the latest registered version, Omitted if none.
+//
+// 返回已注册的最新版本号，没有则返回 Omitted
+func (r *Registry) Latest() Version {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var mv Version
+	for v := range r.calls {
+		if v > mv {
+			mv = v
+		}
+	}
+	return mv
+}
+
+// pending returns the versions to be patched starting from version and the snapshot of their calls,
+// the versions already fixed in history or beyond the target are skipped.
+func (r *Registry) pending(o *options, version Version) (versions []Version, calls map[Version][]*call, err error) {
+	if version <= Omitted {
+		return
+	}
+	last := Omitted
+	if o.history != nil {
+		// check history
+		last = o.history.Fixed()
+		if err = historyErr(o.history); err != nil {
+			// 查询失败时不能当作尚未修复，否则将重复执行全部修补
+			return
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.calls) < 1 {
+		return
+	}
+	var mv Version
+	for v := range r.calls {
+		if v > mv {
+			mv = v
+		}
+	}
+	if o.target > Omitted && o.target < mv {
+		mv = o.target
+	}
+	if version > mv {
+		return
+	}
+	calls = make(map[Version][]*call)
+	for i := version; i <= mv; i++ {
+		if i <= last {
+			continue
+		}
+		versions = append(versions, i)
+		if cs, ok := r.calls[i]; ok {
+			calls[i] = slices.Clone(cs)
+		}
+	}
+	return
+}
+
+// completed returns the number of completed steps of version v if the history provider supports Progress.
+func completed(history History, v Version) (int, error) {
+	if p, ok := history.(Progress); ok {
+		n := p.Completed(v)
+		return n, historyErr(history)
+	}
+	return 0, nil
+}
+
+// Plan returns the pending steps in execution order without executing them, argument version
+// indicates the version from which to start patching, same as Patch.
+//
+// 返回待执行的修补步骤（按执行顺序），但不执行。参数 version 与 Patch 相同，指示从指定的版本开始执行修补。
+func (r *Registry) Plan(ctx context.Context, version Version, opts ...Option) (steps Steps, err error) {
+	o := r.options(opts)
+	versions, calls, err := r.pending(o, version)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		done, err := completed(o.history, v)
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range calls[v] {
+			if i < done {
+				continue
+			}
+			steps = append(steps, &Step{
+				Version:    v,
+				Index:      i,
+				Title:      c.title,
+				Reversible: c.undo != nil,
+			})
+		}
+	}
+	return
+}
+
+// Patch to run hotfixes, argument version indicates the version from which to start patching.
+// If the history provider implements Progress, every completed step is recorded and a partially
+// patched version resumes from its first incomplete step.
+// If a Locker is set, it is acquired before reading the fixed version and released after recording.
+// If the history provider implements ErrorReporter, Patch stops once querying or recording fails.
+//
+// 运行热修补，参数 version 指示从指定的版本开始执行修补。
+// 如果历史记录提供者实现了 Progress 接口，则记录每个已完成的步骤，部分修补的版本从第一个未完成的步骤继续执行。
+// 如果设置了锁，则在读取已修复版本前获取锁，记录完成后释放。
+// 如果历史记录提供者实现了 ErrorReporter 接口，则查询或记录失败时立即中止。
+func (r *Registry) Patch(ctx context.Context, version Version, opts ...Option) (err error) {
+	o := r.options(opts)
+	unlock, err := o.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock(&err)
+
+	if o.strict {
+		ds, err := r.verify(o)
+		if err != nil {
+			return err
+		}
+		if len(ds) > 0 {
+			return fmt.Errorf("%w:\n%s", ErrorDrift, ds)
+		}
+	}
+	versions, calls, err := r.pending(o, version)
+	if err != nil {
+		return err
+	}
+	p, _ := o.history.(Progress)
+	fp, _ := o.history.(Fingerprinter)
+	for _, v := range versions {
+		var summary []string
+		done, err := completed(o.history, v)
+		if err != nil {
+			return err
+		}
+		for i, c := range calls[v] {
+			summary = append(summary, c.title)
+			if i < done {
+				// 已完成的步骤，跳过
+				o.hooks.skip(ctx, &Event{Version: v, Title: c.title})
+				continue
+			}
+			if err := o.run(ctx, v, c, func() {
+				if p != nil {
+					p.RecordStep(v, i, c.title)
+				}
+			}); err != nil {
+				return err
+			}
+			// 进度未能记录时中止，避免在丢失进度的情况下继续执行
+			if err := historyErr(o.history); err != nil {
+				return err
+			}
+		}
+		if o.history != nil {
+			o.history.Record(v, summary)
+		}
+		if fp != nil {
+			fp.RecordFingerprint(v, fingerprint(calls[v]))
+		}
+		if err := historyErr(o.history); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback to run the undo functions in reverse order, version by version and step by step, until the
+// fixed version drops back to target. The history provider must implement Reverter. Steps without undo
+// function block the rollback with ErrorIrreversible unless Force is given.
+//
+// 回滚到目标版本，按版本和步骤的逆序执行撤销函数，历史记录提供者必须实现 Reverter 接口。
+// 存在没有撤销函数的步骤时返回 ErrorIrreversible 错误，除非指定了 Force 选项。
+func (r *Registry) Rollback(ctx context.Context, target Version, opts ...Option) (err error) {
+	o := r.options(opts)
+	if o.history == nil {
+		return ErrorHistoryUndefined
+	}
+	rv, ok := o.history.(Reverter)
+	if !ok {
+		return ErrorHistoryIrreversible
+	}
+	unlock, err := o.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock(&err)
+
+	if target < Omitted {
+		target = Omitted
+	}
+	last := o.history.Fixed()
+	if err = historyErr(o.history); err != nil {
+		return err
+	}
+	if target >= last {
+		return nil
+	}
+
+	// Patch 记录了区间内的每个版本（包括没有注册修补的版本），因此逐个回滚 (target, last] 内的全部版本
+	r.mutex.Lock()
+	var versions []Version
+	calls := make(map[Version][]*call)
+	for v := last; v > target; v-- {
+		versions = append(versions, v)
+		if cs, ok := r.calls[v]; ok {
+			calls[v] = slices.Clone(cs)
+		}
+	}
+	r.mutex.Unlock()
+
+	if !o.force {
+		// 检查全部待回滚的步骤，存在不可撤销的步骤时不执行任何回滚
+		for _, v := range versions {
+			for _, c := range calls[v] {
+				if c.undo == nil {
+					return fmt.Errorf("%w: [v%d] %s", ErrorIrreversible, v, c.title)
+				}
+			}
+		}
+	}
+	for _, v := range versions {
+		cs := calls[v]
+		var summary []string
+		for i := len(cs) - 1; i >= 0; i-- {
+			c := cs[i]
+			if c.undo == nil {
+				continue
+			}
+			summary = append(summary, c.title)
+			if err := c.undo(ctx, v, c.title); err != nil {
+				return err
+			}
+		}
+		rv.Revert(v, summary)
+		if err := historyErr(o.history); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package hotfix
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRollback(t *testing.T) {
+	h, err := OpenFileHistory(filepath.Join(t.TempDir(), "hotfix.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	var trace []string
+	step := func(name string) Hotfix {
+		return func(ctx context.Context, version Version, title string) error {
+			trace = append(trace, name+" "+title)
+			return nil
+		}
+	}
+	r := &Registry{history: h}
+	r.FixIt(1, "a", step("do"), Undo(step("undo")))
+	r.FixIt(2, "b", step("do"), Undo(step("undo")))
+	r.FixIt(2, "c", step("do"), Undo(step("undo")))
+	r.FixIt(3, "d", step("do"))
+	if err = r.Patch(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if v := h.Fixed(); v != 3 {
+		t.Fatalf("patched: want %d, got %d", 3, v)
+	}
+
+	trace = nil
+	if err = r.Rollback(context.Background(), 1); !errors.Is(err, ErrorIrreversible) {
+		t.Errorf("want %v, got %v", ErrorIrreversible, err)
+	}
+	if len(trace) > 0 || h.Fixed() != 3 {
+		t.Errorf("blocked rollback must not run any undo, got %v", trace)
+	}
+
+	if err = r.Rollback(context.Background(), 1, Force()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"undo c", "undo b"}; !slices.Equal(trace, want) {
+		t.Errorf("want %v, got %v", want, trace)
+	}
+	if v := h.Fixed(); v != 1 {
+		t.Errorf("rolled back: want %d, got %d", 1, v)
+	}
+}
+
+func TestRollbackGap(t *testing.T) {
+	h, err := OpenFileHistory(filepath.Join(t.TempDir(), "hotfix.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	var trace []string
+	step := func(name string) Hotfix {
+		return func(ctx context.Context, version Version, title string) error {
+			trace = append(trace, name+" "+title)
+			return nil
+		}
+	}
+	// v2 没有注册修补，但 Patch 同样记录了 v2
+	r := &Registry{history: h}
+	r.FixIt(1, "a", step("do"), Undo(step("undo")))
+	r.FixIt(3, "c", step("do"), Undo(step("undo")))
+	if err = r.Patch(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Rollback(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"do a", "do c", "undo c", "undo a"}; !slices.Equal(trace, want) {
+		t.Errorf("want %v, got %v", want, trace)
+	}
+	if v := h.Fixed(); v != 0 {
+		t.Errorf("rolled back: want %d, got %d", 0, v)
+	}
+	if got := plan(t, r, 1).Versions(); !slices.Equal(got, []Version{1, 3}) {
+		t.Errorf("want %v pending, got %v", []Version{1, 3}, got)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	nop := func(ctx context.Context, version Version, title string) error { return nil }
+	r := &Registry{history: &memoryHistory{fixed: 1}}
+	r.FixIt(1, "a", nop)
+	r.FixIt(2, "b", nop, Undo(nop))
+	r.FixIt(2, "c", nop)
+	r.FixIt(4, "d", nop)
+
+	steps := plan(t, r, 1)
+	want := "[v2] 1. b (reversible)\n[v2] 2. c\n[v4] 1. d"
+	if got := steps.String(); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got := steps.Versions(); !slices.Equal(got, []Version{2, 4}) {
+		t.Errorf("want %v, got %v", []Version{2, 4}, got)
+	}
+	if got := plan(t, r, 5).String(); got != "no pending hotfix" {
+		t.Errorf("want empty plan, got %q", got)
+	}
+}
+
+type memoryHistory struct {
+	fixed Version
+	mutex sync.Mutex
+}
+
+func (h *memoryHistory) Fixed() Version {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.fixed
+}
+
+func (h *memoryHistory) Record(v Version, summary []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.fixed = v
+}
+
+func TestResume(t *testing.T) {
+	h, err := OpenFileHistory(filepath.Join(t.TempDir(), "hotfix.journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	var (
+		trace []string
+		fail  = true
+	)
+	step := func(ctx context.Context, version Version, title string) error {
+		if title == "c" && fail {
+			return errors.New("failed")
+		}
+		trace = append(trace, title)
+		return nil
+	}
+	r := &Registry{history: h}
+	for _, title := range []string{"a", "b", "c", "d"} {
+		r.FixIt(1, title, step)
+	}
+	if err = r.Patch(context.Background(), 1); err == nil {
+		t.Fatal("want error")
+	}
+	if n := h.Completed(1); n != 2 || h.Fixed() != Omitted {
+		t.Fatalf("want 2 completed steps, got %d (fixed %d)", n, h.Fixed())
+	}
+	if got := plan(t, r, 1).String(); got != "[v1] 3. c\n[v1] 4. d" {
+		t.Errorf("unexpected plan %q", got)
+	}
+
+	fail = false
+	if err = r.Patch(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(trace, want) {
+		t.Errorf("want %v, got %v", want, trace)
+	}
+	if n := h.Completed(1); n != 0 || h.Fixed() != 1 {
+		t.Errorf("want fixed version 1 without progress, got %d (completed %d)", h.Fixed(), n)
+	}
+	if es := h.Entries(); !slices.Equal(es[len(es)-1].Steps, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected summary %v", es[len(es)-1].Steps)
+	}
+}
+
+func plan(t *testing.T, r *Registry, version Version) Steps {
+	t.Helper()
+	steps, err := r.Plan(context.Background(), version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return steps
+}
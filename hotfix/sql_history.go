@@ -0,0 +1,381 @@
+package hotfix
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrorTableInvalid = errors.New("hotfix: invalid table name")
+)
+
+const (
+	DefaultTable = "hotfix_history" // 默认历史记录表名
+)
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// migrations of the history table, {table} is replaced by the table name, never modify the released ones.
+//
+// 历史记录表的迁移语句，{table} 替换为表名，已发布的迁移语句不可修改
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS {table} (version INTEGER NOT NULL PRIMARY KEY, steps TEXT NOT NULL, completed INTEGER NOT NULL, fixed INTEGER NOT NULL, host VARCHAR(255) NOT NULL, updated BIGINT NOT NULL)`,
+	`ALTER TABLE {table} ADD COLUMN fingerprint TEXT`,
+}
+
+type txKey struct{}
+
+// TxFromContext returns the transaction started by SQLHistory for the running step, nil if none.
+//
+// 返回 SQLHistory 为正在执行的修补步骤开始的事务，没有则返回 nil
+func TxFromContext(ctx context.Context) *sql.Tx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return nil
+}
+
+// SQLOption is the option of SQLHistory.
+//
+// SQLHistory 选项
+type SQLOption func(h *SQLHistory)
+
+// SQLTable sets the name of the history table, the schema table is named with suffix "_schema".
+//
+// 设置历史记录表名，结构版本表名为其加后缀 "_schema"
+func SQLTable(name string) SQLOption {
+	return func(h *SQLHistory) {
+		h.table = name
+	}
+}
+
+// SQLPlaceholder sets the placeholder of the n-th (1 start) argument, default is "?".
+//
+// 设置第 n 个（1 开始）参数的占位符，默认为 "?"
+func SQLPlaceholder(placeholder func(n int) string) SQLOption {
+	return func(h *SQLHistory) {
+		h.placeholder = placeholder
+	}
+}
+
+// DollarPlaceholder returns the placeholder "$n", e.g. for PostgreSQL.
+//
+// 返回 "$n" 形式的占位符，例如用于 PostgreSQL
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// SQLTransaction runs each step and the record of its progress in one transaction, the step gets the
+// transaction by TxFromContext.
+//
+// 在同一事务中执行每个修补步骤并记录其进度，修补步骤可以通过 TxFromContext 获取事务
+func SQLTransaction() SQLOption {
+	return func(h *SQLHistory) {
+		h.transactional = true
+	}
+}
+
+// SQLHistory is a History provider that stores the applied history in a table of database/sql,
+// the table is created and migrated by NewSQLHistory.
+//
+// 基于 database/sql 的历史记录提供者，历史记录保存在数据库表中，NewSQLHistory 负责创建和迁移该表。
+type SQLHistory struct {
+	db            *sql.DB
+	table         string
+	host          string
+	placeholder   func(n int) string
+	transactional bool
+	tx            *sql.Tx
+	txErr         error
+	err           error
+	mutex         sync.Mutex
+}
+
+// NewSQLHistory creates the history provider on db, and creates or migrates the history table.
+//
+// 创建基于 db 的历史记录提供者，并创建或迁移历史记录表
+func NewSQLHistory(ctx context.Context, db *sql.DB, opts ...SQLOption) (*SQLHistory, error) {
+	h := &SQLHistory{db: db, table: DefaultTable, placeholder: func(int) string { return "?" }}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if !tableName.MatchString(h.table) {
+		return nil, fmt.Errorf("%w: %q", ErrorTableInvalid, h.table)
+	}
+	h.host, _ = os.Hostname()
+	if err := h.migrate(ctx); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// query replaces {table} with the table name and ? with the placeholders.
+func (h *SQLHistory) query(q string) string {
+	q = strings.ReplaceAll(q, "{table}", h.table)
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString(h.placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (h *SQLHistory) migrate(ctx context.Context) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err = tx.ExecContext(ctx, h.query(`CREATE TABLE IF NOT EXISTS {table}_schema (version INTEGER NOT NULL)`)); err != nil {
+		return err
+	}
+	var current sql.NullInt64
+	if err = tx.QueryRowContext(ctx, h.query(`SELECT MAX(version) FROM {table}_schema`)).Scan(&current); err != nil {
+		return err
+	}
+	for i := int(current.Int64); i < len(migrations); i++ {
+		if _, err = tx.ExecContext(ctx, h.query(migrations[i])); err != nil {
+			return fmt.Errorf("hotfix: migrate %s to %d: %w", h.table, i+1, err)
+		}
+		if _, err = tx.ExecContext(ctx, h.query(`INSERT INTO {table}_schema (version) VALUES (?)`), i+1); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction of the running step if any, otherwise the database.
+func (h *SQLHistory) conn() execer {
+	if h.tx != nil {
+		return h.tx
+	}
+	return h.db
+}
+
+func (h *SQLHistory) fail(err error) {
+	if err == nil {
+		return
+	}
+	if h.tx != nil && h.txErr == nil {
+		h.txErr = err
+	}
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// put replaces the row of version v, the DELETE and INSERT run in the transaction of the running step,
+// or in a transaction of their own.
+func (h *SQLHistory) put(v Version, steps []string, completed int, fixed bool) (err error) {
+	ctx := context.Background()
+	var c execer = h.tx
+	if h.tx == nil {
+		var tx *sql.Tx
+		if tx, err = h.db.BeginTx(ctx, nil); err != nil {
+			return
+		}
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+				return
+			}
+			err = tx.Commit()
+		}()
+		c = tx
+	}
+	if _, err = c.ExecContext(ctx, h.query(`DELETE FROM {table} WHERE version = ?`), int(v)); err != nil {
+		return
+	}
+	f := 0
+	if fixed {
+		f = 1
+	}
+	_, err = c.ExecContext(ctx, h.query(`INSERT INTO {table} (version, steps, completed, fixed, host, updated) VALUES (?, ?, ?, ?, ?, ?)`),
+		int(v), strings.Join(steps, "\n"), completed, f, h.host, time.Now().UnixNano())
+	return
+}
+
+// Fixed returns the last fixed version, the error can be retrieved by Err.
+//
+// 返回最近一次修复的版本号，查询错误可通过 Err 获取
+func (h *SQLHistory) Fixed() Version {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var v sql.NullInt64
+	if err := h.conn().QueryRowContext(context.Background(), h.query(`SELECT MAX(version) FROM {table} WHERE fixed = 1`)).Scan(&v); err != nil {
+		h.fail(err)
+	}
+	return Version(v.Int64)
+}
+
+// Record records the fixed version, the error can be retrieved by Err.
+//
+// 记录修复的版本号，写入错误可通过 Err 获取
+func (h *SQLHistory) Record(v Version, summary []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.fail(h.put(v, summary, len(summary), true))
+}
+
+// Revert removes the rolled back version, the error can be retrieved by Err.
+//
+// 删除已回滚的版本号，写入错误可通过 Err 获取
+func (h *SQLHistory) Revert(v Version, summary []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	_, err := h.conn().ExecContext(context.Background(), h.query(`DELETE FROM {table} WHERE version = ?`), int(v))
+	h.fail(err)
+}
+
+// Completed returns the number of completed steps of the version which has not been recorded as fixed.
+//
+// 返回尚未记录为已修复的版本中已完成的步骤数
+func (h *SQLHistory) Completed(v Version) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var n int
+	err := h.conn().QueryRowContext(context.Background(), h.query(`SELECT completed FROM {table} WHERE version = ? AND fixed = 0`), int(v)).Scan(&n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0
+	}
+	h.fail(err)
+	return n
+}
+
+// RecordStep records the completed step, within the transaction of the step if SQLTransaction is set.
+//
+// 记录已完成的步骤，如果设置了 SQLTransaction，则在修补步骤的事务中记录
+func (h *SQLHistory) RecordStep(v Version, index int, title string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.fail(h.put(v, []string{title}, index+1, false))
+}
+
+// Begin starts the transaction of a step if SQLTransaction is set, see Transactional.
+//
+// 如果设置了 SQLTransaction，则开始修补步骤的事务，参见 Transactional 接口
+func (h *SQLHistory) Begin(ctx context.Context) (context.Context, func(err error) error, error) {
+	if !h.transactional {
+		return ctx, func(err error) error { return err }, nil
+	}
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	h.mutex.Lock()
+	h.tx, h.txErr = tx, nil
+	h.mutex.Unlock()
+	return context.WithValue(ctx, txKey{}, tx), func(err error) error {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		h.tx = nil
+		if err == nil {
+			err = h.txErr
+		}
+		if err != nil {
+			return errors.Join(err, ignoreDone(tx.Rollback()))
+		}
+		return tx.Commit()
+	}, nil
+}
+
+func ignoreDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
+
+// RecordFingerprint records the fingerprint of the fixed version, the error can be retrieved by Err.
+//
+// 记录已修复版本的指纹，写入错误可通过 Err 获取
+func (h *SQLHistory) RecordFingerprint(v Version, fp *Fingerprint) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	b, err := json.Marshal(fp)
+	if err == nil {
+		_, err = h.conn().ExecContext(context.Background(), h.query(`UPDATE {table} SET fingerprint = ? WHERE version = ?`), string(b), int(v))
+	}
+	h.fail(err)
+}
+
+// Fingerprints returns the fingerprints of the applied versions, the step titles are used for the
+// versions recorded before fingerprints were supported.
+//
+// 返回已修复版本的指纹，支持指纹之前记录的版本使用步骤标题作为指纹
+func (h *SQLHistory) Fingerprints() map[Version]*Fingerprint {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	rows, err := h.conn().QueryContext(context.Background(), h.query(`SELECT version, steps, fingerprint FROM {table} WHERE fixed = 1`))
+	if err != nil {
+		h.fail(err)
+		return nil
+	}
+	defer rows.Close()
+
+	result := make(map[Version]*Fingerprint)
+	for rows.Next() {
+		var (
+			v     int
+			steps string
+			print sql.NullString
+		)
+		if err = rows.Scan(&v, &steps, &print); err != nil {
+			h.fail(err)
+			return nil
+		}
+		fp := &Fingerprint{}
+		if !print.Valid || json.Unmarshal([]byte(print.String), fp) != nil {
+			fp = &Fingerprint{}
+			if steps != "" {
+				fp.Steps = strings.Split(steps, "\n")
+			}
+			fp.Sum = fp.sum()
+		}
+		result[Version(v)] = fp
+	}
+	h.fail(rows.Err())
+	return result
+}
+
+// Err returns the first error occurred while querying or recording.
+//
+// 返回查询或记录时发生的第一个错误
+func (h *SQLHistory) Err() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.err
+}
@@ -0,0 +1,486 @@
+package hotfix
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is an in-process driver which understands the simple statements used by SQLHistory.
+type fakeDriver struct {
+	mutex sync.Mutex
+	dbs   map[string]*fakeDB
+}
+
+type fakeTable struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+type fakeDB struct {
+	mutex  sync.Mutex
+	tables map[string]*fakeTable
+	fail   func(query string) error // 注入的执行错误
+}
+
+func (d *fakeDB) failWith(fail func(query string) error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.fail = fail
+}
+
+func (d *fakeDB) snapshot() map[string]*fakeTable {
+	c := make(map[string]*fakeTable, len(d.tables))
+	for k, t := range d.tables {
+		rows := make([][]driver.Value, 0, len(t.rows))
+		for _, r := range t.rows {
+			rows = append(rows, slices.Clone(r))
+		}
+		c[k] = &fakeTable{cols: slices.Clone(t.cols), rows: rows}
+	}
+	return c
+}
+
+var (
+	fakeCreate = regexp.MustCompile(`^CREATE TABLE IF NOT EXISTS (\w+) \((.*)\)$`)
+	fakeAlter  = regexp.MustCompile(`^ALTER TABLE (\w+) ADD COLUMN (\w+)`)
+	fakeInsert = regexp.MustCompile(`^INSERT INTO (\w+) \(([^)]*)\) VALUES`)
+	fakeUpdate = regexp.MustCompile(`^UPDATE (\w+) SET (\w+) = \? WHERE (.+)$`)
+	fakeDelete = regexp.MustCompile(`^DELETE FROM (\w+) WHERE (.+)$`)
+	fakeSelect = regexp.MustCompile(`^SELECT (.+?) FROM (\w+)(?: WHERE (.+))?$`)
+	fakeMax    = regexp.MustCompile(`^MAX\((\w+)\)$`)
+)
+
+func (t *fakeTable) col(name string) int {
+	return slices.Index(t.cols, name)
+}
+
+// match evaluates the conditions "col = ?" or "col = literal" joined by AND.
+func (t *fakeTable) match(where string, args []driver.Value) (func(row []driver.Value) bool, error) {
+	if where == "" {
+		return func([]driver.Value) bool { return true }, nil
+	}
+	type cond struct {
+		col   int
+		value string
+	}
+	var conds []cond
+	for _, c := range strings.Split(where, " AND ") {
+		name, value, _ := strings.Cut(c, " = ")
+		i := t.col(name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+		if value == "?" {
+			value = fmt.Sprint(args[0])
+			args = args[1:]
+		}
+		conds = append(conds, cond{i, value})
+	}
+	return func(row []driver.Value) bool {
+		for _, c := range conds {
+			if fmt.Sprint(row[c.col]) != c.value {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (d *fakeDB) exec(query string, args []driver.Value) (cols []string, rows [][]driver.Value, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.fail != nil {
+		if err = d.fail(query); err != nil {
+			return
+		}
+	}
+	if m := fakeCreate.FindStringSubmatch(query); m != nil {
+		if _, ok := d.tables[m[1]]; !ok {
+			t := &fakeTable{}
+			for _, c := range strings.Split(m[2], ", ") {
+				t.cols = append(t.cols, strings.Fields(c)[0])
+			}
+			d.tables[m[1]] = t
+		}
+		return
+	}
+	var m []string
+	table := func(name string) (*fakeTable, error) {
+		if t, ok := d.tables[name]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("no such table %s", name)
+	}
+	if m = fakeAlter.FindStringSubmatch(query); m != nil {
+		t, err := table(m[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		t.cols = append(t.cols, m[2])
+		for i := range t.rows {
+			t.rows[i] = append(t.rows[i], nil)
+		}
+		return nil, nil, nil
+	}
+	if m = fakeInsert.FindStringSubmatch(query); m != nil {
+		t, err := table(m[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		row := make([]driver.Value, len(t.cols))
+		for i, c := range strings.Split(m[2], ", ") {
+			row[t.col(c)] = args[i]
+		}
+		t.rows = append(t.rows, row)
+		return nil, nil, nil
+	}
+	if m = fakeUpdate.FindStringSubmatch(query); m != nil {
+		t, err := table(m[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := t.match(m[3], args[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range t.rows {
+			if f(r) {
+				r[t.col(m[2])] = args[0]
+			}
+		}
+		return nil, nil, nil
+	}
+	if m = fakeDelete.FindStringSubmatch(query); m != nil {
+		t, err := table(m[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := t.match(m[2], args)
+		if err != nil {
+			return nil, nil, err
+		}
+		t.rows = slices.DeleteFunc(t.rows, f)
+		return nil, nil, nil
+	}
+	if m = fakeSelect.FindStringSubmatch(query); m != nil {
+		projection, where := m[1], m[3]
+		t, err := table(m[2])
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := t.match(where, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		if x := fakeMax.FindStringSubmatch(projection); x != nil {
+			var max driver.Value
+			i := t.col(x[1])
+			for _, r := range t.rows {
+				if f(r) && (max == nil || r[i].(int64) > max.(int64)) {
+					max = r[i]
+				}
+			}
+			return []string{projection}, [][]driver.Value{{max}}, nil
+		}
+		cols = strings.Split(projection, ", ")
+		for _, r := range t.rows {
+			if !f(r) {
+				continue
+			}
+			var row []driver.Value
+			for _, c := range cols {
+				row = append(row, r[t.col(c)])
+			}
+			rows = append(rows, row)
+		}
+		return cols, rows, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported statement %q", query)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.dbs == nil {
+		d.dbs = make(map[string]*fakeDB)
+	}
+	db, ok := d.dbs[name]
+	if !ok {
+		db = &fakeDB{tables: make(map[string]*fakeTable)}
+		d.dbs[name] = db
+	}
+	return &fakeConn{db: db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mutex.Lock()
+	defer c.db.mutex.Unlock()
+
+	return &fakeTx{db: c.db, saved: c.db.snapshot()}, nil
+}
+
+type fakeTx struct {
+	db    *fakeDB
+	saved map[string]*fakeTable
+}
+
+func (t *fakeTx) Commit() error {
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.db.mutex.Lock()
+	defer t.db.mutex.Unlock()
+
+	t.db.tables = t.saved
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if _, _, err := s.db.exec(s.query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	cols, rows, err := s.db.exec(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{cols: cols, rows: rows}, nil
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.cols
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var fakeSQL = &fakeDriver{}
+
+// db returns the database opened with the DSN name.
+func (d *fakeDriver) db(name string) *fakeDB {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.dbs[name]
+}
+
+func init() {
+	sql.Register("hotfix-fake", fakeSQL)
+}
+
+func TestSQLHistory(t *testing.T) {
+	ctx := context.Background()
+	// 假驱动的数据库按 DSN 全局共享，每次运行使用独立的 DSN
+	db, err := sql.Open("hotfix-fake", t.Name()+"/"+t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	h, err := NewSQLHistory(ctx, db, SQLTransaction())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 再次创建时不重复迁移
+	if _, err = NewSQLHistory(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	var schema int
+	if err = db.QueryRowContext(ctx, `SELECT MAX(version) FROM hotfix_history_schema`).Scan(&schema); err != nil || schema != len(migrations) {
+		t.Errorf("want schema version %d, got %d (%v)", len(migrations), schema, err)
+	}
+	if _, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS data (id INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+
+	fail := true
+	insert := func(id int) Hotfix {
+		return func(ctx context.Context, version Version, title string) error {
+			tx := TxFromContext(ctx)
+			if tx == nil {
+				return errors.New("no transaction")
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO data (id) VALUES (?)`, id); err != nil {
+				return err
+			}
+			if id == 2 && fail {
+				return errors.New("failed")
+			}
+			return nil
+		}
+	}
+	r := NewRegistry()
+	r.SetHistoryProvider(h)
+	r.FixIt(1, "a", insert(1))
+	r.FixIt(1, "b", insert(2))
+
+	if err = r.Patch(ctx, 1); err == nil {
+		t.Fatal("want error")
+	}
+	if n := h.Completed(1); n != 1 || h.Fixed() != Omitted {
+		t.Errorf("want 1 completed step, got %d (fixed %d)", n, h.Fixed())
+	}
+	ids := func() (ids []int) {
+		rows, err := db.QueryContext(ctx, `SELECT id FROM data`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			_ = rows.Scan(&id)
+			ids = append(ids, id)
+		}
+		return
+	}
+	if got := ids(); !slices.Equal(got, []int{1}) {
+		t.Errorf("failed step must be rolled back, got %v", got)
+	}
+
+	fail = false
+	if err = r.Patch(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("want %v, got %v", []int{1, 2}, got)
+	}
+	if v := h.Fixed(); v != 1 || h.Err() != nil {
+		t.Errorf("want fixed version 1, got %d (%v)", v, h.Err())
+	}
+	if ds, err := r.Verify(ctx); err != nil || len(ds) > 0 {
+		t.Errorf("want no drift, got %v (%v)", ds, err)
+	}
+	r.FixIt(1, "c", insert(3))
+	if ds, err := r.Verify(ctx); err != nil || ds.String() != `[v1] 3. added: "c"` {
+		t.Errorf("want added step, got %v (%v)", ds, err)
+	}
+}
+
+func TestSQLHistoryTable(t *testing.T) {
+	// 假驱动的数据库按 DSN 全局共享，每次运行使用独立的 DSN
+	db, err := sql.Open("hotfix-fake", t.Name()+"/"+t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = NewSQLHistory(context.Background(), db, SQLTable("history; DROP TABLE x")); !errors.Is(err, ErrorTableInvalid) {
+		t.Errorf("want %v, got %v", ErrorTableInvalid, err)
+	}
+}
+
+func TestSQLHistoryError(t *testing.T) {
+	ctx := context.Background()
+	dsn := t.Name() + "/" + t.TempDir()
+	db, err := sql.Open("hotfix-fake", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	h, err := NewSQLHistory(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := fakeSQL.db(dsn)
+
+	var trace []string
+	step := func(ctx context.Context, version Version, title string) error {
+		trace = append(trace, title)
+		return nil
+	}
+	r := NewRegistry()
+	r.FixIt(1, "a", step)
+	r.FixIt(1, "b", step)
+
+	// 第二个步骤的进度写入失败，DELETE 随 INSERT 一并回滚，Patch 中止
+	failed := errors.New("failed")
+	inserts := 0
+	fake.failWith(func(query string) error {
+		if strings.HasPrefix(query, "INSERT INTO hotfix_history ") {
+			if inserts++; inserts > 1 {
+				return failed
+			}
+		}
+		return nil
+	})
+	if err = r.Patch(ctx, 1, WithHistory(h)); !errors.Is(err, failed) {
+		t.Fatalf("want %v, got %v", failed, err)
+	}
+	fake.failWith(nil)
+	if n := h.Completed(1); n != 1 {
+		t.Errorf("want the progress of step a kept, got %d", n)
+	}
+
+	// 查询已修复版本失败时不执行任何修补
+	trace = nil
+	if h, err = NewSQLHistory(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	fake.failWith(func(query string) error {
+		if strings.HasPrefix(query, "SELECT MAX(version) FROM hotfix_history ") {
+			return failed
+		}
+		return nil
+	})
+	if err = r.Patch(ctx, 1, WithHistory(h)); !errors.Is(err, failed) {
+		t.Errorf("want %v, got %v", failed, err)
+	}
+	if len(trace) > 0 {
+		t.Errorf("want no step run, got %v", trace)
+	}
+}
@@ -2,7 +2,8 @@ package hotfix
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"time"
 )
 
 type Version int // Hotfix version number
@@ -13,76 +14,148 @@ const (
 
 type Hotfix func(ctx context.Context, version Version, title string) error
 
+var (
+	ErrorHistoryUndefined    = errors.New("hotfix: history provider undefined")
+	ErrorHistoryIrreversible = errors.New("hotfix: history provider does not support revert")
+	ErrorIrreversible        = errors.New("hotfix: irreversible step")
+)
+
 type call struct {
-	title  string
-	hotfix Hotfix
+	title   string
+	hotfix  Hotfix
+	undo    Hotfix
+	timeout time.Duration
+	retry   *Retry
+	hash    string
+}
+
+// FixOption is the option of a hotfix function.
+//
+// 热修补函数选项
+type FixOption func(c *call)
+
+// Undo registers the undo function of the hotfix, which is called by Rollback.
+//
+// 注册热修补函数对应的撤销函数，回滚时调用
+func Undo(undo Hotfix) FixOption {
+	return func(c *call) {
+		c.undo = undo
+	}
+}
+
+// Timeout sets the timeout of the hotfix, which overrides the StepTimeout of Patch.
+//
+// 设置热修补函数的超时时间，优先于 Patch 的 StepTimeout 选项
+func Timeout(timeout time.Duration) FixOption {
+	return func(c *call) {
+		c.timeout = timeout
+	}
 }
 
-type fixes struct {
+// Retries sets the retry policy of the hotfix, which overrides the StepRetry of Patch.
+//
+// 设置热修补函数的重试策略，优先于 Patch 的 StepRetry 选项
+func Retries(retry *Retry) FixOption {
+	return func(c *call) {
+		c.retry = retry
+	}
+}
+
+// Hash sets the content hash of the hotfix, e.g. the checksum of its SQL script, which is recorded
+// in the fingerprint of the version and verified by Verify.
+//
+// 设置热修补函数的内容哈希，例如其 SQL 脚本的校验和，记录在版本指纹中并由 Verify 校验
+func Hash(hash string) FixOption {
+	return func(c *call) {
+		c.hash = hash
+	}
+}
+
+type options struct {
 	history History
-	calls   map[Version][]*call
-	mutex   sync.Mutex
-	once    sync.Once
+	locker  Locker
+	hooks   *Hooks
+	timeout time.Duration
+	retry   *Retry
+	target  Version
+	force   bool
+	strict  bool
 }
 
-func (f *fixes) append(ver Version, title string, hotfixes ...Hotfix) {
-	f.mutex.Lock()
-	defer f.mutex.Unlock()
+// Option is the option of Patch, Plan and Rollback.
+//
+// 修补、计划与回滚选项
+type Option func(o *options)
 
-	if f.calls == nil {
-		f.calls = make(map[Version][]*call)
+// WithHistory to use the history provider instead of the provider of the registry, e.g. the history
+// of a tenant database.
+//
+// 使用指定的历史记录提供者代替注册表的历史记录提供者，例如租户数据库的历史记录
+func WithHistory(history History) Option {
+	return func(o *options) {
+		o.history = history
 	}
-	for _, hotfix := range hotfixes {
-		var cs []*call
-		if v, ok := f.calls[ver]; ok {
-			cs = v
-		}
-		f.calls[ver] = append(cs, &call{
-			title:  title,
-			hotfix: hotfix,
-		})
+}
+
+// WithLocker to use the locker instead of the locker of the registry.
+//
+// 使用指定的锁代替注册表的锁
+func WithLocker(locker Locker) Option {
+	return func(o *options) {
+		o.locker = locker
 	}
 }
 
-func (f *fixes) do(ctx context.Context, version Version) (err error) {
-	f.once.Do(func() {
-		if version <= Omitted {
-			return
-		}
-		last := Omitted
-		if f.history != nil {
-			// check history
-			last = f.history.Fixed()
-		}
-		if len(f.calls) < 1 {
-			return
-		}
-		var mv Version
-		for v := range f.calls {
-			if v > mv {
-				mv = v
-			}
-		}
-		if version > mv {
-			return
-		}
-		for i := version; i <= mv; i++ {
-			if i <= last {
-				continue
-			}
-			var summary []string
-			if cs, ok := f.calls[i]; ok {
-				for _, c := range cs {
-					summary = append(summary, c.title)
-					if err = c.hotfix(ctx, i, c.title); err != nil {
-						return
-					}
-				}
-			}
-			if f.history != nil {
-				f.history.Record(i, summary)
-			}
-		}
-	})
-	return
+// WithHooks to set the lifecycle hooks of steps.
+//
+// 设置修补步骤的生命周期钩子
+func WithHooks(hooks *Hooks) Option {
+	return func(o *options) {
+		o.hooks = hooks
+	}
+}
+
+// StepTimeout sets the default timeout of each step.
+//
+// 设置每个修补步骤的默认超时时间
+func StepTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// StepRetry sets the default retry policy of each step.
+//
+// 设置每个修补步骤的默认重试策略
+func StepRetry(retry *Retry) Option {
+	return func(o *options) {
+		o.retry = retry
+	}
+}
+
+// Target to patch up to the target version instead of the latest registered version.
+//
+// 修补到目标版本为止，而不是已注册的最新版本
+func Target(target Version) Option {
+	return func(o *options) {
+		o.target = target
+	}
+}
+
+// Strict to verify the registered steps before patching, Patch returns ErrorDrift if any drift is found.
+//
+// 修补前校验注册的修补步骤，存在偏差时 Patch 返回 ErrorDrift 错误
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// Force to skip the steps without undo function instead of blocking the rollback.
+//
+// 强制回滚，跳过没有撤销函数的步骤，而不是中止回滚
+func Force() Option {
+	return func(o *options) {
+		o.force = true
+	}
 }
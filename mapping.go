@@ -0,0 +1,133 @@
+package golang
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrorDuplicateKey = errors.New("golang: duplicate key")
+)
+
+// Duplicate is the policy of the duplicate keys when converting a slice to a map.
+//
+// 切片转换为映射时重复键的处理策略
+type Duplicate int
+
+const (
+	DuplicateLast     Duplicate = iota // 保留最后一个，与 Map 相同
+	DuplicateFirst                     // 保留第一个
+	DuplicatePriority                  // 保留优先级最高的，相同时保留已存在的，与 MapFunc 相同
+	DuplicateError                     // 返回 DuplicateKeyError
+)
+
+// DuplicateKeyError is the error of the duplicate key with the indexes of the conflicting elements,
+// which matches ErrorDuplicateKey by errors.Is.
+//
+// 重复键错误，包含冲突元素的索引，可使用 errors.Is 匹配 ErrorDuplicateKey
+type DuplicateKeyError struct {
+	Key    any // 重复的键
+	First  int // 第一个元素的索引
+	Second int // 第二个元素的索引
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("golang: duplicate key %v at index %d and %d", e.Key, e.First, e.Second)
+}
+
+func (e *DuplicateKeyError) Unwrap() error {
+	return ErrorDuplicateKey
+}
+
+type slot struct {
+	index    int
+	priority int
+}
+
+// mapping converts the slice to a map, resolve returns the value of the duplicate key and whether to
+// replace the existing slot.
+func mapping[E any, K comparable, V any](ss []E, f func(index int, ele E) (key K, value V, priority int, err error),
+	resolve func(key K, existing slot, current slot, old, value V) (v V, replace bool, err error)) (result map[K]V, err error) {
+	if ss == nil {
+		return
+	}
+	result = make(map[K]V, len(ss))
+	slots := make(map[K]slot, len(ss))
+	for i, s := range ss {
+		k, v, p, e := f(i, s)
+		if e != nil {
+			return nil, fmt.Errorf("golang: element %d: %w", i, e)
+		}
+		current := slot{index: i, priority: p}
+		existing, ok := slots[k]
+		if ok {
+			var replace bool
+			if v, replace, e = resolve(k, existing, current, result[k], v); e != nil {
+				return nil, e
+			}
+			if !replace {
+				current = existing
+			}
+		}
+		result[k] = v
+		slots[k] = current
+	}
+	return
+}
+
+// MapPolicy converts the slice to a map, use f to convert each element to a new key, value and priority,
+// the duplicate keys are resolved by policy. It stops and returns the error if f returns an error, the
+// error is wrapped with the index of the element.
+//
+// 切片转换为映射，使用函数 f 将每个元素转换为键、值与优先级，重复键按 policy 处理。
+// f 返回错误时停止转换并返回该错误，错误中包含元素的索引。
+func MapPolicy[E any, K comparable, V any](ss []E, f func(index int, ele E) (key K, value V, priority int, err error), policy Duplicate) (map[K]V, error) {
+	return mapping(ss, f, func(key K, existing, current slot, old, value V) (V, bool, error) {
+		switch policy {
+		case DuplicateFirst:
+			return old, false, nil
+		case DuplicatePriority:
+			if current.priority > existing.priority {
+				return value, true, nil
+			}
+			return old, false, nil
+		case DuplicateError:
+			return old, false, &DuplicateKeyError{Key: key, First: existing.index, Second: current.index}
+		default:
+			return value, true, nil
+		}
+	})
+}
+
+// MapMerge converts the slice to a map, use f to convert each element to a new key and value, the values
+// of the duplicate keys are merged by merge in the order of the elements. It stops and returns the error
+// if f or merge returns an error.
+//
+// 切片转换为映射，使用函数 f 将每个元素转换为键与值，重复键的值按元素顺序使用 merge 合并。f 或 merge 返回错误时停止转换并返回该错误。
+func MapMerge[E any, K comparable, V any](ss []E, f func(index int, ele E) (key K, value V, err error),
+	merge func(key K, existing, value V) (V, error)) (map[K]V, error) {
+	return mapping(ss, func(index int, ele E) (key K, value V, priority int, err error) {
+		key, value, err = f(index, ele)
+		return
+	}, func(key K, existing, current slot, old, value V) (V, bool, error) {
+		v, err := merge(key, old, value)
+		return v, true, err
+	})
+}
+
+// TryMap is the variant of Map whose callback returns an error, see MapPolicy.
+//
+// Map 的回调函数可返回错误的版本，参见 MapPolicy
+func TryMap[E any, K comparable, V any](ss []E, f func(index int, ele E) (key K, value V, err error)) (map[K]V, error) {
+	return MapPolicy(ss, func(index int, ele E) (key K, value V, priority int, err error) {
+		key, value, err = f(index, ele)
+		return
+	}, DuplicateLast)
+}
+
+// TryMapFunc is the variant of MapFunc whose callback returns an error, see MapPolicy.
+//
+// MapFunc 的回调函数可返回错误的版本，参见 MapPolicy
+func TryMapFunc[E any, K comparable, V any](ss []E, f func(index int, ele E) (key K, value V, priority int, err error)) (map[K]V, error) {
+	return MapPolicy(ss, f, DuplicatePriority)
+}
@@ -0,0 +1,64 @@
+package golang
+
+import (
+	"errors"
+	"maps"
+	"strconv"
+	"testing"
+)
+
+func TestMapPolicy(t *testing.T) {
+	ss := []string{"a:1", "b:2", "a:3", "a:2"}
+	f := func(i int, s string) (string, int, int, error) {
+		v, err := strconv.Atoi(s[2:])
+		return s[:1], v, v, err
+	}
+	for i, r := range []struct {
+		policy Duplicate
+		want   map[string]int
+	}{
+		{DuplicateLast, map[string]int{"a": 2, "b": 2}},
+		{DuplicateFirst, map[string]int{"a": 1, "b": 2}},
+		{DuplicatePriority, map[string]int{"a": 3, "b": 2}},
+	} {
+		if got, err := MapPolicy(ss, f, r.policy); err != nil || !maps.Equal(got, r.want) {
+			t.Errorf("%d. want %v, got %v, %v", i+1, r.want, got, err)
+		}
+	}
+
+	_, err := MapPolicy(ss, f, DuplicateError)
+	var de *DuplicateKeyError
+	if !errors.Is(err, ErrorDuplicateKey) || !errors.As(err, &de) || de.Key != "a" || de.First != 0 || de.Second != 2 {
+		t.Errorf("want duplicate key a at 0 and 2, got %v", err)
+	}
+
+	_, err = TryMap([]string{"a:1", "b:x"}, func(i int, s string) (string, int, error) {
+		v, err := strconv.Atoi(s[2:])
+		return s[:1], v, err
+	})
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("want %v, got %v", strconv.ErrSyntax, err)
+	}
+	if m, err := TryMapFunc([]string(nil), f); m != nil || err != nil {
+		t.Errorf("want nil map, got %v, %v", m, err)
+	}
+}
+
+func TestMapMerge(t *testing.T) {
+	got, err := MapMerge([]string{"go", "rust", "gleam", "ruby"}, func(i int, s string) (byte, []string, error) {
+		return s[0], []string{s}, nil
+	}, func(key byte, existing, value []string) ([]string, error) {
+		return append(existing, value...), nil
+	})
+	if err != nil || len(got) != 2 || len(got['g']) != 2 || got['r'][1] != "ruby" {
+		t.Errorf("MapMerge: got %v, %v", got, err)
+	}
+	failed := errors.New("conflict")
+	if _, err = MapMerge([]int{1, 1}, func(i int, e int) (int, int, error) {
+		return e, i, nil
+	}, func(key int, existing, value int) (int, error) {
+		return 0, failed
+	}); !errors.Is(err, failed) {
+		t.Errorf("want %v, got %v", failed, err)
+	}
+}
@@ -0,0 +1,188 @@
+package golang
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// each calls f for index 0 to n-1 concurrently by workers (less than 1 uses GOMAXPROCS), the indexes are
+// claimed in small batches, stops early and cancels ctx of f on the first error.
+func each(ctx context.Context, n, workers int, f func(ctx context.Context, index int) error) error {
+	if n == 0 {
+		return ctx.Err()
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+	batch := max(1, n/(workers*8))
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				start := int(next.Add(int64(batch))) - batch
+				if start >= n {
+					return
+				}
+				for i := start; i < min(start+batch, n); i++ {
+					if ctx.Err() != nil {
+						return
+					}
+					if err := f(ctx, i); err != nil {
+						// 仅第一个原因生效
+						cancel(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+// ParallelCast is the concurrent variant of Cast, calls cast concurrently by workers (less than 1 uses
+// GOMAXPROCS), the result keeps the order of the elements, the omitted elements are skipped. It stops
+// early and cancels ctx of the running casts on the first error.
+//
+// Cast 的并发版本，使用 workers 个协程（小于 1 时使用 GOMAXPROCS）并发调用 cast，结果保持元素的顺序，
+// omitted 为 true 的元素被跳过。出现第一个错误时提前结束，并取消正在执行的 cast 的 ctx。
+func ParallelCast[E, R any](ctx context.Context, ss []E, workers int, cast func(ctx context.Context, index int, ele E) (v R, omitted bool, err error)) (result []R, err error) {
+	if ss == nil {
+		return
+	}
+	var (
+		values  = make([]R, len(ss))
+		omitted = make([]bool, len(ss))
+	)
+	if err = each(ctx, len(ss), workers, func(ctx context.Context, i int) (e error) {
+		values[i], omitted[i], e = cast(ctx, i, ss[i])
+		return
+	}); err != nil {
+		return nil, err
+	}
+	result = values[:0]
+	for i, v := range values {
+		if !omitted[i] {
+			result = append(result, v)
+		}
+	}
+	return
+}
+
+// ParallelMap is the concurrent variant of Map, see ParallelCast, the existing key-value pairs are
+// overwritten in the order of the elements, same as Map.
+//
+// Map 的并发版本，参见 ParallelCast，与 Map 相同，按元素顺序覆盖已存在的键值对
+func ParallelMap[E any, K comparable, V any](ctx context.Context, ss []E, workers int, f func(ctx context.Context, index int, ele E) (key K, value V, err error)) (result map[K]V, err error) {
+	if ss == nil {
+		return
+	}
+	var (
+		keys   = make([]K, len(ss))
+		values = make([]V, len(ss))
+	)
+	if err = each(ctx, len(ss), workers, func(ctx context.Context, i int) (e error) {
+		keys[i], values[i], e = f(ctx, i, ss[i])
+		return
+	}); err != nil {
+		return nil, err
+	}
+	result = make(map[K]V, len(ss))
+	for i, k := range keys {
+		result[k] = values[i]
+	}
+	return
+}
+
+// ParallelReplace is the concurrent variant of Replace, see ParallelCast, the elements replaced before
+// the error remain replaced.
+//
+// Replace 的并发版本，参见 ParallelCast，出错前已替换的元素保持替换后的值
+func ParallelReplace[E any](ctx context.Context, ss []E, workers int, f func(ctx context.Context, index int, ele E) (v E, omitted bool, err error)) error {
+	return each(ctx, len(ss), workers, func(ctx context.Context, i int) error {
+		v, omitted, err := f(ctx, i, ss[i])
+		if err == nil && !omitted {
+			ss[i] = v
+		}
+		return err
+	})
+}
+
+// ParallelReduce is the concurrent variant of Reduce, the slice is split into contiguous parts by workers
+// (less than 1 uses GOMAXPROCS), each part is reduced by f from initial, and the results of the parts are
+// combined in order by combine. initial must be the identity of combine, e.g. 0 for sum, and combine must be
+// associative. It stops early on the first error.
+//
+// Reduce 的并发版本，将切片按 workers（小于 1 时使用 GOMAXPROCS）分为连续的若干部分，各部分从 initial 开始使用 f 收敛，
+// 再使用 combine 按顺序合并各部分的结果。initial 必须是 combine 的单位元，例如求和时为 0，combine 必须满足结合律。
+// 出现第一个错误时提前结束。
+func ParallelReduce[S ~[]E, E any, R any](ctx context.Context, ss S, workers int, f func(ctx context.Context, r R, ele E) (R, error),
+	combine func(a, b R) R, initial R) (R, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	parts := max(1, min(workers, len(ss)))
+	size := (len(ss) + parts - 1) / parts
+	results := make([]R, parts)
+	err := each(ctx, parts, workers, func(ctx context.Context, p int) (err error) {
+		r := initial
+		for _, e := range ss[min(p*size, len(ss)):min((p+1)*size, len(ss))] {
+			if err = ctx.Err(); err != nil {
+				return
+			}
+			if r, err = f(ctx, r, e); err != nil {
+				return
+			}
+		}
+		results[p] = r
+		return
+	})
+	if err != nil {
+		return initial, err
+	}
+	r := results[0]
+	for _, v := range results[1:] {
+		r = combine(r, v)
+	}
+	return r, nil
+}
+
+// TreeReduce reduces the slice by combining the adjacent elements pairwise level by level concurrently
+// like a tree, combine must be associative, the order of the operands is kept. Returns the zero value if
+// the slice is empty. It stops early on the first error.
+//
+// 树形收敛，逐层并发地两两合并相邻元素，combine 必须满足结合律，保持操作数的顺序。切片为空时返回零值，出现第一个错误时提前结束。
+func TreeReduce[S ~[]E, E any](ctx context.Context, ss S, workers int, combine func(ctx context.Context, a, b E) (E, error)) (result E, err error) {
+	if len(ss) == 0 {
+		return
+	}
+	level := make([]E, len(ss))
+	copy(level, ss)
+	for len(level) > 1 {
+		pairs := len(level) / 2
+		next := make([]E, (len(level)+1)/2)
+		if err = each(ctx, pairs, workers, func(ctx context.Context, i int) (e error) {
+			next[i], e = combine(ctx, level[2*i], level[2*i+1])
+			return
+		}); err != nil {
+			return
+		}
+		if len(level)%2 == 1 {
+			// 奇数个元素，最后一个直接进入下一层
+			next[pairs] = level[len(level)-1]
+		}
+		level = next
+	}
+	return level[0], ctx.Err()
+}
@@ -0,0 +1,93 @@
+package golang
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelCast(t *testing.T) {
+	ss := make([]int, 1000)
+	for i := range ss {
+		ss[i] = i
+	}
+	want := Cast(ss, func(i int, e int) (string, bool) {
+		return strconv.Itoa(e), e%3 == 0
+	})
+	got, err := ParallelCast(context.Background(), ss, 7, func(ctx context.Context, i int, e int) (string, bool, error) {
+		return strconv.Itoa(e), e%3 == 0, nil
+	})
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("ParallelCast: want %d elements, got %d, %v", len(want), len(got), err)
+	}
+	if got, _ = ParallelCast(context.Background(), []int{}, 0, func(ctx context.Context, i int, e int) (string, bool, error) {
+		return "", false, nil
+	}); got == nil || len(got) != 0 {
+		t.Errorf("ParallelCast: want empty slice, got %v", got)
+	}
+}
+
+func TestParallelCastError(t *testing.T) {
+	ss := make([]int, 10000)
+	failed := errors.New("failed")
+	var calls atomic.Int64
+	_, err := ParallelCast(context.Background(), ss, 4, func(ctx context.Context, i int, e int) (int, bool, error) {
+		calls.Add(1)
+		if i == 10 {
+			return 0, false, failed
+		}
+		return e, false, nil
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("want %v, got %v", failed, err)
+	}
+	if n := calls.Load(); n == int64(len(ss)) {
+		t.Errorf("want early stop, got %d calls", n)
+	}
+}
+
+func TestParallelMapReplace(t *testing.T) {
+	ss := []string{"a", "b", "a", "c"}
+	m, err := ParallelMap(context.Background(), ss, 2, func(ctx context.Context, i int, e string) (string, int, error) {
+		return e, i, nil
+	})
+	if err != nil || len(m) != 3 || m["a"] != 2 {
+		t.Errorf("ParallelMap: want later index overwritten, got %v, %v", m, err)
+	}
+	err = ParallelReplace(context.Background(), ss, 2, func(ctx context.Context, i int, e string) (string, bool, error) {
+		return e + e, e == "b", nil
+	})
+	if want := []string{"aa", "b", "aa", "cc"}; err != nil || !slices.Equal(ss, want) {
+		t.Errorf("ParallelReplace: want %v, got %v, %v", want, ss, err)
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	ss := make([]int, 1001)
+	for i := range ss {
+		ss[i] = i
+	}
+	sum, err := ParallelReduce(context.Background(), ss, 4, func(ctx context.Context, r int, e int) (int, error) {
+		return r + e, nil
+	}, func(a, b int) int { return a + b }, 0)
+	if err != nil || sum != 500500 {
+		t.Errorf("ParallelReduce: want 500500, got %d, %v", sum, err)
+	}
+	words := []string{"a", "b", "c", "d", "e"}
+	s, err := TreeReduce(context.Background(), words, 2, func(ctx context.Context, a, b string) (string, error) {
+		return a + b, nil
+	})
+	if err != nil || s != "abcde" {
+		t.Errorf("TreeReduce: want abcde, got %q, %v", s, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = TreeReduce(ctx, words, 2, func(ctx context.Context, a, b string) (string, error) {
+		return a + b, nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("TreeReduce: want %v, got %v", context.Canceled, err)
+	}
+}
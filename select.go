@@ -0,0 +1,190 @@
+package golang
+
+import (
+	"container/heap"
+	"math/bits"
+	"slices"
+)
+
+type ranked[E any] struct {
+	value E
+	index int
+}
+
+// bounded is the heap of the k best elements with the worst one on the top.
+type bounded[E any] struct {
+	items   []ranked[E]
+	compare func(a, b E) int
+}
+
+// better reports whether x is better than y, the earlier one is better if equal.
+func (h *bounded[E]) better(x, y ranked[E]) bool {
+	if c := h.compare(x.value, y.value); c != 0 {
+		return c > 0
+	}
+	return x.index < y.index
+}
+
+func (h *bounded[E]) Len() int           { return len(h.items) }
+func (h *bounded[E]) Less(i, j int) bool { return h.better(h.items[j], h.items[i]) }
+func (h *bounded[E]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *bounded[E]) Push(x any)         { h.items = append(h.items, x.(ranked[E])) }
+func (h *bounded[E]) Pop() any {
+	x := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return x
+}
+
+// TopK returns the k largest elements in descending order by a bounded heap, the earlier element comes first
+// if equal. It runs in O(n log k) time without sorting or modifying the slice.
+//
+// 使用有界堆返回最大的 k 个元素，按降序排列，相等时较早的元素在前。时间复杂度为 O(n log k)，不排序也不修改原切片
+func TopK[E any](ss []E, k int, compare func(a, b E) int) []E {
+	k = min(k, len(ss))
+	if k < 1 {
+		return nil
+	}
+	h := &bounded[E]{items: make([]ranked[E], 0, k), compare: compare}
+	for i, s := range ss {
+		x := ranked[E]{value: s, index: i}
+		if h.Len() < k {
+			heap.Push(h, x)
+		} else if h.better(x, h.items[0]) {
+			h.items[0] = x
+			heap.Fix(h, 0)
+		}
+	}
+	slices.SortFunc(h.items, func(a, b ranked[E]) int {
+		if h.better(a, b) {
+			return -1
+		}
+		return 1
+	})
+	result := make([]E, k)
+	for i, x := range h.items {
+		result[i] = x.value
+	}
+	return result
+}
+
+// BottomK returns the k smallest elements in ascending order, see TopK.
+//
+// 返回最小的 k 个元素，按升序排列，参见 TopK
+func BottomK[E any](ss []E, k int, compare func(a, b E) int) []E {
+	return TopK(ss, k, func(a, b E) int { return compare(b, a) })
+}
+
+// permute sorts ss in place by the cached keys.
+func permute[E, K any](ss []E, key func(ele E) K, compare func(a, b K) int, stable bool) {
+	if len(ss) < 2 {
+		return
+	}
+	keys := make([]K, len(ss))
+	order := make([]int, len(ss))
+	for i, s := range ss {
+		keys[i] = key(s)
+		order[i] = i
+	}
+	c := func(i, j int) int { return compare(keys[i], keys[j]) }
+	if stable {
+		slices.SortStableFunc(order, c)
+	} else {
+		slices.SortFunc(order, c)
+	}
+	sorted := make([]E, len(ss))
+	for i, j := range order {
+		sorted[i] = ss[j]
+	}
+	copy(ss, sorted)
+}
+
+// SortBy sorts the slice in place by the keys of the elements, the key is computed only once for each
+// element (Schwartzian transform), which is useful for expensive keys.
+//
+// 按元素的键就地排序，每个元素的键仅计算一次（施瓦茨变换），适用于计算代价较高的键
+func SortBy[E, K any](ss []E, key func(ele E) K, compare func(a, b K) int) {
+	permute(ss, key, compare, false)
+}
+
+// StableSortBy is the stable variant of SortBy, the equal elements keep the original order.
+//
+// SortBy 的稳定版本，相等的元素保持原有顺序
+func StableSortBy[E, K any](ss []E, key func(ele E) K, compare func(a, b K) int) {
+	permute(ss, key, compare, true)
+}
+
+// MinBy returns the smallest element and its index, the first one if equal, index is -1 if the slice is empty.
+//
+// 返回最小的元素及其索引，相等时返回第一个，切片为空时 index 为 -1
+func MinBy[E any](ss []E, compare func(a, b E) int) (found E, index int) {
+	index = -1
+	for i, s := range ss {
+		if index < 0 || compare(s, found) < 0 {
+			found, index = s, i
+		}
+	}
+	return
+}
+
+// MaxBy returns the largest element and its index, the first one if equal, index is -1 if the slice is empty.
+//
+// 返回最大的元素及其索引，相等时返回第一个，切片为空时 index 为 -1
+func MaxBy[E any](ss []E, compare func(a, b E) int) (found E, index int) {
+	return MinBy(ss, func(a, b E) int { return compare(b, a) })
+}
+
+// Nth reorders the slice in place by quick-select so that ss[n] is the element which would be there if the
+// slice were sorted, the elements before it are not greater and the elements after it are not less, returns
+// ss[n] and false if n is out of range. It runs in O(n) time on average, and falls back to sorting if the
+// partitions are unbalanced.
+//
+// 使用快速选择就地重排切片，使 ss[n] 为切片排序后该位置上的元素，其之前的元素均不大于它，之后的元素均不小于它，
+// 返回 ss[n]，n 超出范围时返回 false。平均时间复杂度为 O(n)，分区不均衡时退化为排序。
+func Nth[E any](ss []E, n int, compare func(a, b E) int) (v E, ok bool) {
+	if n < 0 || n >= len(ss) {
+		return
+	}
+	lo, hi := 0, len(ss)
+	for budget := 2 * bits.Len(uint(len(ss))); hi-lo > 1; budget-- {
+		if budget == 0 {
+			slices.SortFunc(ss[lo:hi], compare)
+			break
+		}
+		// 三数取中作为枢轴
+		m := lo + (hi-lo)/2
+		if compare(ss[m], ss[lo]) < 0 {
+			ss[m], ss[lo] = ss[lo], ss[m]
+		}
+		if compare(ss[hi-1], ss[lo]) < 0 {
+			ss[hi-1], ss[lo] = ss[lo], ss[hi-1]
+		}
+		if compare(ss[hi-1], ss[m]) < 0 {
+			ss[hi-1], ss[m] = ss[m], ss[hi-1]
+		}
+		pivot := ss[m]
+		// 三路划分：[lo, lt) 小于枢轴，[lt, gt) 等于枢轴，[gt, hi) 大于枢轴
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			switch c := compare(ss[i], pivot); {
+			case c < 0:
+				ss[lt], ss[i] = ss[i], ss[lt]
+				lt++
+				i++
+			case c > 0:
+				gt--
+				ss[gt], ss[i] = ss[i], ss[gt]
+			default:
+				i++
+			}
+		}
+		switch {
+		case n < lt:
+			hi = lt
+		case n >= gt:
+			lo = gt
+		default:
+			return ss[n], true
+		}
+	}
+	return ss[n], true
+}
@@ -0,0 +1,111 @@
+package golang
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	ss := []int{5, 1, 9, 3, 7, 9, 2}
+	for i, r := range []struct {
+		got, want []int
+	}{
+		{TopK(ss, 3, cmp.Compare[int]), []int{9, 9, 7}},
+		{BottomK(ss, 3, cmp.Compare[int]), []int{1, 2, 3}},
+		{TopK(ss, 10, cmp.Compare[int]), []int{9, 9, 7, 5, 3, 2, 1}},
+		{TopK(ss, 0, cmp.Compare[int]), nil},
+	} {
+		if !slices.Equal(r.got, r.want) {
+			t.Errorf("%d. want %v, got %v", i+1, r.want, r.got)
+		}
+	}
+	// 相等时较早的元素在前
+	type item struct {
+		score int
+		name  string
+	}
+	items := []item{{1, "a"}, {2, "b"}, {2, "c"}, {2, "d"}}
+	got := TopK(items, 2, CompareBy(func(e item) int { return e.score }))
+	if got[0].name != "b" || got[1].name != "c" {
+		t.Errorf("want b, c, got %v", got)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	calls := 0
+	ss := []string{"bb", "A", "ccc", "a", "B"}
+	StableSortBy(ss, func(s string) string {
+		calls++
+		return strings.ToLower(s)
+	}, strings.Compare)
+	if want := []string{"A", "a", "B", "bb", "ccc"}; !slices.Equal(ss, want) {
+		t.Errorf("want %v, got %v", want, ss)
+	}
+	if calls != len(ss) {
+		t.Errorf("want %d key calls, got %d", len(ss), calls)
+	}
+	SortBy(ss, func(s string) int { return -len(s) }, cmp.Compare[int])
+	if ss[0] != "ccc" || len(ss[4]) != 1 {
+		t.Errorf("SortBy: got %v", ss)
+	}
+}
+
+func TestMinMaxBy(t *testing.T) {
+	ss := []int{3, 1, 4, 1, 5}
+	if v, i := MinBy(ss, cmp.Compare[int]); v != 1 || i != 1 {
+		t.Errorf("MinBy: got %d at %d", v, i)
+	}
+	if v, i := MaxBy(ss, cmp.Compare[int]); v != 5 || i != 4 {
+		t.Errorf("MaxBy: got %d at %d", v, i)
+	}
+	if _, i := MinBy([]int{}, cmp.Compare[int]); i != -1 {
+		t.Errorf("MinBy of empty: want -1, got %d", i)
+	}
+}
+
+func TestNth(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(5, 6))
+	for round := 0; round < 200; round++ {
+		ss := make([]int, rnd.IntN(100)+1)
+		for i := range ss {
+			ss[i] = rnd.IntN(20)
+		}
+		sorted := slices.Sorted(slices.Values(ss))
+		n := rnd.IntN(len(ss))
+		v, ok := Nth(ss, n, cmp.Compare[int])
+		if !ok || v != sorted[n] || ss[n] != v {
+			t.Fatalf("Nth(%d): want %d, got %d", n, sorted[n], v)
+		}
+		for i, e := range ss {
+			if i < n && e > v || i > n && e < v {
+				t.Fatalf("Nth(%d): %d at %d is on the wrong side of %d", n, e, i, v)
+			}
+		}
+	}
+	if _, ok := Nth([]int{1}, 1, cmp.Compare[int]); ok {
+		t.Error("Nth out of range: want false")
+	}
+}
+
+func BenchmarkTopK(b *testing.B) {
+	rnd := rand.New(rand.NewPCG(7, 8))
+	ss := make([]int, 100000)
+	for i := range ss {
+		ss[i] = rnd.Int()
+	}
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			TopK(ss, 10, cmp.Compare[int])
+		}
+	})
+	b.Run("sort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c := slices.Clone(ss)
+			slices.SortFunc(c, func(a, b int) int { return cmp.Compare(b, a) })
+			_ = c[:10]
+		}
+	})
+}
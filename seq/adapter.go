@@ -0,0 +1,109 @@
+package seq
+
+import (
+	"iter"
+
+	"github.com/keepitlight/golang/slices"
+	"github.com/keepitlight/golang/tuples"
+)
+
+// From returns the sequence of the elements of the slice.
+//
+// 返回切片元素的序列
+func From[T any](ss []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range ss {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Of returns the sequence of the arguments.
+//
+// 返回参数的序列
+func Of[T any](vs ...T) iter.Seq[T] {
+	return From(vs)
+}
+
+// ToSlice collects the elements of the sequence into a new slice.
+//
+// 将序列的元素收集到新的切片中
+func ToSlice[T any](s iter.Seq[T]) []T {
+	var result []T
+	for v := range s {
+		result = append(result, v)
+	}
+	return result
+}
+
+// FromMap returns the sequence of the key-value pairs of the map in unspecified order.
+//
+// 返回映射键值对的序列，顺序不确定
+func FromMap[K comparable, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// ToMap collects the key-value pairs of the sequence into a new map, the later pairs overwrite the
+// earlier ones with the same key.
+//
+// 将序列的键值对收集到新的映射中，相同键的后者覆盖前者
+func ToMap[K comparable, V any](s iter.Seq2[K, V]) map[K]V {
+	result := make(map[K]V)
+	for k, v := range s {
+		result[k] = v
+	}
+	return result
+}
+
+// Pairs converts the sequence of key-value pairs to the sequence of tuples.
+//
+// 将键值对序列转换为二元组序列
+func Pairs[K, V any](s iter.Seq2[K, V]) iter.Seq[*tuples.Pair[K, V]] {
+	return func(yield func(*tuples.Pair[K, V]) bool) {
+		for k, v := range s {
+			if !yield(&tuples.Pair[K, V]{A: k, B: v}) {
+				return
+			}
+		}
+	}
+}
+
+// Unpairs converts the sequence of tuples to the sequence of key-value pairs, nil tuples are skipped.
+//
+// 将二元组序列转换为键值对序列，跳过 nil 二元组
+func Unpairs[K, V any](s iter.Seq[*tuples.Pair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for p := range s {
+			if p != nil && !yield(p.A, p.B) {
+				return
+			}
+		}
+	}
+}
+
+// FromStack returns the sequence of the elements of the stack from top to bottom, the stack is locked
+// for reading during the iteration if it has a read-write lock.
+//
+// 返回栈中从栈顶到栈底的元素序列，栈使用读写锁时在遍历期间加读锁
+func FromStack[T any](st *slices.Stack[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		st.Each(func(elem T, index int) (stop bool) {
+			return !yield(elem)
+		})
+	}
+}
+
+// ToStack pushes the elements of the sequence into a new stack in order, the last element is on the top.
+//
+// 将序列的元素依次压入新的栈，最后一个元素位于栈顶
+func ToStack[T any](s iter.Seq[T]) *slices.Stack[T] {
+	return slices.Push(ToSlice(s)...)
+}
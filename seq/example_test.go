@@ -0,0 +1,90 @@
+package seq_test
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/keepitlight/golang/seq"
+	gslices "github.com/keepitlight/golang/slices"
+)
+
+func Example() {
+	numbers := seq.Of(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	even := seq.Filter(numbers, func(v int) bool { return v%2 == 0 })
+	squares := seq.Map(even, func(v int) int { return v * v })
+	fmt.Println(seq.ToSlice(seq.Take(squares, 3)))
+	fmt.Println(seq.Reduce(squares, func(r, v int) int { return r + v }, 0))
+	// Output:
+	// [4 16 36]
+	// 220
+}
+
+func ExampleFlatMap() {
+	words := seq.FlatMap(seq.Of("a b", "c", "d e f"), func(s string) iter.Seq[string] {
+		return seq.From(strings.Fields(s))
+	})
+	fmt.Println(seq.ToSlice(seq.Skip(words, 1)))
+	fmt.Println(seq.ToSlice(seq.TakeWhile(words, func(s string) bool { return s < "d" })))
+	// Output:
+	// [b c d e f]
+	// [a b c]
+}
+
+func ExampleChunk() {
+	for chunk := range seq.Chunk(seq.Of(1, 2, 3, 4, 5), 2) {
+		fmt.Println(chunk)
+	}
+	// Output:
+	// [1 2]
+	// [3 4]
+	// [5]
+}
+
+func ExampleWindow() {
+	var windows [][]int
+	for w := range seq.Window(seq.Of(1, 2, 3, 4, 5), 3) {
+		// 窗口仅在下一次迭代前有效
+		windows = append(windows, slices.Clone(w))
+	}
+	fmt.Println(windows)
+	// Output:
+	// [[1 2 3] [2 3 4] [3 4 5]]
+}
+
+func ExampleZip() {
+	for p := range seq.Zip(seq.Of("a", "b", "c"), seq.Of(1, 2)) {
+		fmt.Println(p.A, p.B)
+	}
+	// Output:
+	// a 1
+	// b 2
+}
+
+func ExampleEnumerate() {
+	for i, v := range seq.Enumerate(seq.Dedup(seq.Concat(seq.Of("x", "y"), seq.Of("y", "z")))) {
+		fmt.Println(i, v)
+	}
+	// Output:
+	// 0 x
+	// 1 y
+	// 2 z
+}
+
+func ExampleToMap() {
+	m := seq.ToMap(seq.Unpairs(seq.Pairs(seq.FromMap(map[string]int{"a": 1, "b": 2}))))
+	fmt.Println(slices.Sorted(maps.Keys(m)), m["a"], m["b"])
+	// Output:
+	// [a b] 1 2
+}
+
+func ExampleFromStack() {
+	st := gslices.RWLock(1, 2, 3)
+	fmt.Println(seq.ToSlice(seq.FromStack(st)))
+	fmt.Println(seq.ToStack(seq.Of(4, 5)).Peek())
+	// Output:
+	// [3 2 1]
+	// 5 true
+}
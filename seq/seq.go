@@ -0,0 +1,236 @@
+// Package seq provides lazy combinators over iter.Seq and iter.Seq2, the elements are streamed one by one
+// without building intermediate slices.
+//
+// 基于 iter.Seq 与 iter.Seq2 的惰性组合函数，逐个流式处理元素，不创建中间切片
+package seq
+
+import (
+	"iter"
+
+	"github.com/keepitlight/golang/tuples"
+)
+
+// Filter returns the sequence of the elements for which f returns true.
+//
+// 返回 f 为 true 的元素序列
+func Filter[T any](s iter.Seq[T], f func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			if f(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Map returns the sequence of the elements converted by f.
+//
+// 返回经 f 转换的元素序列
+func Map[T, R any](s iter.Seq[T], f func(T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for v := range s {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// FlatMap returns the concatenation of the sequences returned by f for each element.
+//
+// 返回 f 对每个元素返回的序列的串联
+func FlatMap[T, R any](s iter.Seq[T], f func(T) iter.Seq[R]) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for v := range s {
+			for r := range f(v) {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Take returns the sequence of the first n elements.
+//
+// 返回前 n 个元素的序列
+func Take[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n < 1 {
+			return
+		}
+		i := 0
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+			if i++; i >= n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns the sequence without the first n elements.
+//
+// 返回跳过前 n 个元素的序列
+func Skip[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for v := range s {
+			if i++; i <= n {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns the sequence of the leading elements for which f returns true.
+//
+// 返回开头连续 f 为 true 的元素序列
+func TakeWhile[T any](s iter.Seq[T], f func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			if !f(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns the sequence of the consecutive chunks of size elements, the last chunk may be shorter,
+// each chunk is a new slice. It yields nothing if size is less than 1.
+//
+// 返回每 size 个连续元素组成的块序列，最后一块可能较短，每块都是新的切片。size 小于 1 时不产出任何块
+func Chunk[T any](s iter.Seq[T], size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size < 1 {
+			return
+		}
+		var chunk []T
+		for v := range s {
+			if chunk == nil {
+				chunk = make([]T, 0, size)
+			}
+			if chunk = append(chunk, v); len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window returns the sequence of the sliding windows of size elements, the window slides one element at a
+// time. The window shares a buffer and is only valid until the next iteration, use slices.Clone to keep it.
+// It yields nothing if size is less than 1 or there are fewer than size elements.
+//
+// 返回 size 个元素的滑动窗口序列，窗口每次滑动一个元素。窗口共用缓冲，仅在下一次迭代前有效，需保留时请使用 slices.Clone。
+// size 小于 1 或元素少于 size 个时不产出任何窗口
+func Window[T any](s iter.Seq[T], size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size < 1 {
+			return
+		}
+		// 每个元素写入两次，buf[i:i+size] 总是连续的窗口
+		buf := make([]T, 2*size)
+		n := 0
+		for v := range s {
+			i := n % size
+			buf[i], buf[i+size] = v, v
+			if n++; n >= size && !yield(buf[n%size:n%size+size]) {
+				return
+			}
+		}
+	}
+}
+
+// Zip returns the sequence of the pairs of the elements of a and b, stops when either is exhausted.
+//
+// 返回 a 与 b 元素组成的二元组序列，任一序列结束时结束
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq[*tuples.Pair[A, B]] {
+	return func(yield func(*tuples.Pair[A, B]) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+		for x := range a {
+			y, ok := next()
+			if !ok || !yield(&tuples.Pair[A, B]{A: x, B: y}) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns the sequence of the 0 start index and the element.
+//
+// 返回 0 开始的序号与元素的序列
+func Enumerate[T any](s iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range s {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Dedup returns the sequence without the duplicate elements, keeping the first seen ones, the seen
+// elements are kept in memory.
+//
+// 返回去除重复元素的序列，保留首次出现的元素，已出现的元素保存在内存中
+func Dedup[T comparable](s iter.Seq[T]) iter.Seq[T] {
+	return DedupBy(s, func(v T) T { return v })
+}
+
+// DedupBy returns the sequence without the elements with duplicate keys, see Dedup.
+//
+// 返回去除键重复元素的序列，参见 Dedup
+func DedupBy[T any, K comparable](s iter.Seq[T], key func(T) K) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[K]struct{})
+		for v := range s {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Concat returns the concatenation of the sequences.
+//
+// 返回多个序列的串联
+func Concat[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, s := range seqs {
+			for v := range s {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Reduce returns the result of reducing the sequence using the function f from initial.
+//
+// 收敛，从 initial 开始使用函数 f 依次计算每个元素，返回计算结果
+func Reduce[T, R any](s iter.Seq[T], f func(R, T) R, initial R) R {
+	for v := range s {
+		initial = f(initial, v)
+	}
+	return initial
+}
@@ -0,0 +1,57 @@
+package sets_test
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/keepitlight/golang/ranges"
+	"github.com/keepitlight/golang/sets"
+)
+
+func ExampleSet() {
+	a := sets.New(1, 2, 3, 4)
+	b := sets.New(3, 4, 5)
+	sorted := func(s *sets.Set[int]) []int {
+		return slices.Sorted(s.All())
+	}
+	fmt.Println(sorted(a.Union(b)), sorted(a.Intersect(b)), sorted(a.Difference(b)), sorted(a.SymmetricDifference(b)))
+	fmt.Println(a.Contains(2), sets.New(3, 4).IsSubset(b), a.IsSuperset(b))
+
+	var c sets.Set[string]
+	_ = json.Unmarshal([]byte(`["x","y","x"]`), &c)
+	fmt.Println(c.Len(), c.Contains("y"))
+	// Output:
+	// [1 2 3 4 5] [3 4] [1 2] [1 2 5]
+	// true true false
+	// 2 true
+}
+
+func ExampleOrdered() {
+	s := sets.NewOrdered("c", "a", "b", "a")
+	s.Add("d").Remove("a")
+	b, _ := json.Marshal(s)
+	fmt.Println(string(b))
+	fmt.Println(s.Union(sets.NewOrdered("e", "c")).Values())
+	// Output:
+	// ["c","b","d"]
+	// [c b d e]
+}
+
+func ExampleSorted() {
+	s := sets.NewSorted(cmp.Compare[int], 5, 1, 9, 3, 7, 3)
+	fmt.Println(s.Values())
+	fmt.Println(slices.Collect(s.Between(ranges.New(2, 7, cmp.Compare[int]))))
+	fmt.Println(s.Intersect(sets.NewSorted(cmp.Compare[int], 3, 4, 5)).Values())
+
+	desc := sets.NewSorted(func(a, b int) int { return cmp.Compare(b, a) })
+	_ = json.Unmarshal([]byte(`[2, 8, 4]`), desc)
+	b, _ := json.Marshal(desc)
+	fmt.Println(string(b))
+	// Output:
+	// [1 3 5 7 9]
+	// [3 5 7]
+	// [3 5]
+	// [8,4,2]
+}
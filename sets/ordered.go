@@ -0,0 +1,241 @@
+package sets
+
+import (
+	"encoding/json"
+	"iter"
+)
+
+type element[T comparable] struct {
+	value      T
+	prev, next *element[T]
+}
+
+// Ordered is the set of comparable elements which keeps the insertion order, adding an existing element
+// does not change its position. The zero value is an empty set ready to use, Ordered is not safe for
+// concurrent use.
+//
+// 保持插入顺序的可比较元素集合，添加已存在的元素不改变其位置。零值为可直接使用的空集合，Ordered 不能安全地并发使用
+type Ordered[T comparable] struct {
+	items      map[T]*element[T]
+	head, tail *element[T]
+}
+
+// NewOrdered creates an insertion-ordered set of the items.
+//
+// 使用 items 创建按插入顺序排列的集合
+func NewOrdered[T comparable](items ...T) *Ordered[T] {
+	s := &Ordered[T]{items: make(map[T]*element[T], len(items))}
+	return s.Add(items...)
+}
+
+// CollectOrdered creates an insertion-ordered set of the elements of the sequence.
+//
+// 使用序列的元素创建按插入顺序排列的集合
+func CollectOrdered[T comparable](seq iter.Seq[T]) *Ordered[T] {
+	s := NewOrdered[T]()
+	for v := range seq {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add appends the items which are not in the set.
+//
+// 在末尾添加不在集合中的元素
+func (s *Ordered[T]) Add(items ...T) *Ordered[T] {
+	if s.items == nil {
+		s.items = make(map[T]*element[T], len(items))
+	}
+	for _, v := range items {
+		if _, ok := s.items[v]; ok {
+			continue
+		}
+		e := &element[T]{value: v, prev: s.tail}
+		if s.tail != nil {
+			s.tail.next = e
+		} else {
+			s.head = e
+		}
+		s.tail = e
+		s.items[v] = e
+	}
+	return s
+}
+
+// Remove removes the items.
+//
+// 删除元素
+func (s *Ordered[T]) Remove(items ...T) *Ordered[T] {
+	for _, v := range items {
+		e, ok := s.items[v]
+		if !ok {
+			continue
+		}
+		if e.prev != nil {
+			e.prev.next = e.next
+		} else {
+			s.head = e.next
+		}
+		if e.next != nil {
+			e.next.prev = e.prev
+		} else {
+			s.tail = e.prev
+		}
+		delete(s.items, v)
+	}
+	return s
+}
+
+// Contains reports whether v is in the set.
+//
+// 检查 v 是否在集合中
+func (s *Ordered[T]) Contains(v T) bool {
+	_, ok := s.items[v]
+	return ok
+}
+
+// Len returns the number of elements.
+//
+// 返回元素个数
+func (s *Ordered[T]) Len() int {
+	return len(s.items)
+}
+
+// Clear removes all elements.
+//
+// 清空集合
+func (s *Ordered[T]) Clear() {
+	clear(s.items)
+	s.head, s.tail = nil, nil
+}
+
+// Clone returns a copy of the set.
+//
+// 返回集合的副本
+func (s *Ordered[T]) Clone() *Ordered[T] {
+	return CollectOrdered(s.All())
+}
+
+// All returns the sequence of the elements in insertion order.
+//
+// 按插入顺序返回元素序列
+func (s *Ordered[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := s.head; e != nil; e = e.next {
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the elements in insertion order.
+//
+// 按插入顺序返回元素切片
+func (s *Ordered[T]) Values() []T {
+	result := make([]T, 0, len(s.items))
+	for v := range s.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// Union returns a new set of the elements in s or o, the elements of s come first.
+//
+// 并集，返回在 s 或 o 中的元素组成的新集合，s 的元素在前
+func (s *Ordered[T]) Union(o *Ordered[T]) *Ordered[T] {
+	c := s.Clone()
+	for v := range o.All() {
+		c.Add(v)
+	}
+	return c
+}
+
+// Intersect returns a new set of the elements in both s and o, in the order of s.
+//
+// 交集，返回同时在 s 与 o 中的元素组成的新集合，按 s 的顺序排列
+func (s *Ordered[T]) Intersect(o *Ordered[T]) *Ordered[T] {
+	c := NewOrdered[T]()
+	for v := range s.All() {
+		if o.Contains(v) {
+			c.Add(v)
+		}
+	}
+	return c
+}
+
+// Difference returns a new set of the elements in s but not in o, in the order of s.
+//
+// 差集，返回在 s 中但不在 o 中的元素组成的新集合，按 s 的顺序排列
+func (s *Ordered[T]) Difference(o *Ordered[T]) *Ordered[T] {
+	c := NewOrdered[T]()
+	for v := range s.All() {
+		if !o.Contains(v) {
+			c.Add(v)
+		}
+	}
+	return c
+}
+
+// SymmetricDifference returns a new set of the elements in either s or o but not both, the elements of
+// s come first.
+//
+// 对称差集，返回仅在 s 或仅在 o 中的元素组成的新集合，s 的元素在前
+func (s *Ordered[T]) SymmetricDifference(o *Ordered[T]) *Ordered[T] {
+	c := s.Difference(o)
+	for v := range o.All() {
+		if !s.Contains(v) {
+			c.Add(v)
+		}
+	}
+	return c
+}
+
+// IsSubset reports whether every element of s is in o.
+//
+// 检查 s 是否为 o 的子集
+func (s *Ordered[T]) IsSubset(o *Ordered[T]) bool {
+	if s.Len() > o.Len() {
+		return false
+	}
+	for v := range s.items {
+		if !o.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether every element of o is in s.
+//
+// 检查 s 是否为 o 的超集
+func (s *Ordered[T]) IsSuperset(o *Ordered[T]) bool {
+	return o.IsSubset(s)
+}
+
+// Equal reports whether s and o contain the same elements, regardless of the order.
+//
+// 检查 s 与 o 是否包含相同的元素，不考虑顺序
+func (s *Ordered[T]) Equal(o *Ordered[T]) bool {
+	return s.Len() == o.Len() && s.IsSubset(o)
+}
+
+// MarshalJSON marshals the set as an array in insertion order.
+//
+// 按插入顺序将集合序列化为数组
+func (s *Ordered[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Values())
+}
+
+// UnmarshalJSON unmarshals the array into the set in order, the duplicate elements are ignored.
+//
+// 按顺序将数组反序列化为集合，忽略重复元素
+func (s *Ordered[T]) UnmarshalJSON(b []byte) error {
+	var items []T
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	s.Clear()
+	s.Add(items...)
+	return nil
+}
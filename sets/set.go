@@ -0,0 +1,220 @@
+// Package sets provides the generic sets: the hash set Set, the insertion-ordered set Ordered and the
+// sorted set Sorted with a comparator.
+//
+// 泛型集合：哈希集合 Set、按插入顺序排列的集合 Ordered 以及使用比较函数排序的集合 Sorted
+package sets
+
+import (
+	"encoding/json"
+	"iter"
+)
+
+// Set is the hash set of comparable elements, the zero value is an empty set ready to use, the order of
+// the elements is unspecified. Set is not safe for concurrent use.
+//
+// 可比较元素的哈希集合，零值为可直接使用的空集合，元素顺序不确定。Set 不能安全地并发使用
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// New creates a set of the items.
+//
+// 使用 items 创建集合
+func New[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(items))}
+	return s.Add(items...)
+}
+
+// Collect creates a set of the elements of the sequence.
+//
+// 使用序列的元素创建集合
+func Collect[T comparable](seq iter.Seq[T]) *Set[T] {
+	s := New[T]()
+	for v := range seq {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add adds the items.
+//
+// 添加元素
+func (s *Set[T]) Add(items ...T) *Set[T] {
+	if s.items == nil {
+		s.items = make(map[T]struct{}, len(items))
+	}
+	for _, v := range items {
+		s.items[v] = struct{}{}
+	}
+	return s
+}
+
+// Remove removes the items.
+//
+// 删除元素
+func (s *Set[T]) Remove(items ...T) *Set[T] {
+	for _, v := range items {
+		delete(s.items, v)
+	}
+	return s
+}
+
+// Contains reports whether v is in the set.
+//
+// 检查 v 是否在集合中
+func (s *Set[T]) Contains(v T) bool {
+	_, ok := s.items[v]
+	return ok
+}
+
+// Len returns the number of elements.
+//
+// 返回元素个数
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Clear removes all elements.
+//
+// 清空集合
+func (s *Set[T]) Clear() {
+	clear(s.items)
+}
+
+// Clone returns a copy of the set.
+//
+// 返回集合的副本
+func (s *Set[T]) Clone() *Set[T] {
+	c := &Set[T]{items: make(map[T]struct{}, len(s.items))}
+	for v := range s.items {
+		c.items[v] = struct{}{}
+	}
+	return c
+}
+
+// All returns the sequence of the elements in unspecified order.
+//
+// 返回元素序列，顺序不确定
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s.items {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the elements in unspecified order.
+//
+// 返回元素切片，顺序不确定
+func (s *Set[T]) Values() []T {
+	result := make([]T, 0, len(s.items))
+	for v := range s.items {
+		result = append(result, v)
+	}
+	return result
+}
+
+// Union returns a new set of the elements in s or o.
+//
+// 并集，返回在 s 或 o 中的元素组成的新集合
+func (s *Set[T]) Union(o *Set[T]) *Set[T] {
+	c := s.Clone()
+	for v := range o.items {
+		c.items[v] = struct{}{}
+	}
+	return c
+}
+
+// Intersect returns a new set of the elements in both s and o.
+//
+// 交集，返回同时在 s 与 o 中的元素组成的新集合
+func (s *Set[T]) Intersect(o *Set[T]) *Set[T] {
+	a, b := s, o
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	c := New[T]()
+	for v := range a.items {
+		if b.Contains(v) {
+			c.items[v] = struct{}{}
+		}
+	}
+	return c
+}
+
+// Difference returns a new set of the elements in s but not in o.
+//
+// 差集，返回在 s 中但不在 o 中的元素组成的新集合
+func (s *Set[T]) Difference(o *Set[T]) *Set[T] {
+	c := New[T]()
+	for v := range s.items {
+		if !o.Contains(v) {
+			c.items[v] = struct{}{}
+		}
+	}
+	return c
+}
+
+// SymmetricDifference returns a new set of the elements in either s or o but not both.
+//
+// 对称差集，返回仅在 s 或仅在 o 中的元素组成的新集合
+func (s *Set[T]) SymmetricDifference(o *Set[T]) *Set[T] {
+	c := s.Difference(o)
+	for v := range o.items {
+		if !s.Contains(v) {
+			c.items[v] = struct{}{}
+		}
+	}
+	return c
+}
+
+// IsSubset reports whether every element of s is in o.
+//
+// 检查 s 是否为 o 的子集
+func (s *Set[T]) IsSubset(o *Set[T]) bool {
+	if s.Len() > o.Len() {
+		return false
+	}
+	for v := range s.items {
+		if !o.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether every element of o is in s.
+//
+// 检查 s 是否为 o 的超集
+func (s *Set[T]) IsSuperset(o *Set[T]) bool {
+	return o.IsSubset(s)
+}
+
+// Equal reports whether s and o contain the same elements.
+//
+// 检查 s 与 o 是否包含相同的元素
+func (s *Set[T]) Equal(o *Set[T]) bool {
+	return s.Len() == o.Len() && s.IsSubset(o)
+}
+
+// MarshalJSON marshals the set as an array in unspecified order.
+//
+// 将集合序列化为数组，顺序不确定
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Values())
+}
+
+// UnmarshalJSON unmarshals the array into the set, the duplicate elements are ignored.
+//
+// 将数组反序列化为集合，忽略重复元素
+func (s *Set[T]) UnmarshalJSON(b []byte) error {
+	var items []T
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	s.items = make(map[T]struct{}, len(items))
+	s.Add(items...)
+	return nil
+}
@@ -0,0 +1,86 @@
+package sets
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAlgebra(t *testing.T) {
+	a, b := []int{1, 2, 3, 4}, []int{3, 4, 5, 6}
+	for i, r := range []struct {
+		name   string
+		set    func() []int
+		sorted func() []int
+		order  func() []int
+		want   []int
+	}{
+		{"union",
+			func() []int { return New(a...).Union(New(b...)).Values() },
+			func() []int {
+				return NewSorted(cmp.Compare[int], a...).Union(NewSorted(cmp.Compare[int], b...)).Values()
+			},
+			func() []int { return NewOrdered(a...).Union(NewOrdered(b...)).Values() },
+			[]int{1, 2, 3, 4, 5, 6}},
+		{"intersect",
+			func() []int { return New(a...).Intersect(New(b...)).Values() },
+			func() []int {
+				return NewSorted(cmp.Compare[int], a...).Intersect(NewSorted(cmp.Compare[int], b...)).Values()
+			},
+			func() []int { return NewOrdered(a...).Intersect(NewOrdered(b...)).Values() },
+			[]int{3, 4}},
+		{"difference",
+			func() []int { return New(a...).Difference(New(b...)).Values() },
+			func() []int {
+				return NewSorted(cmp.Compare[int], a...).Difference(NewSorted(cmp.Compare[int], b...)).Values()
+			},
+			func() []int { return NewOrdered(a...).Difference(NewOrdered(b...)).Values() },
+			[]int{1, 2}},
+		{"symmetric difference",
+			func() []int { return New(a...).SymmetricDifference(New(b...)).Values() },
+			func() []int {
+				return NewSorted(cmp.Compare[int], a...).SymmetricDifference(NewSorted(cmp.Compare[int], b...)).Values()
+			},
+			func() []int { return NewOrdered(a...).SymmetricDifference(NewOrdered(b...)).Values() },
+			[]int{1, 2, 5, 6}},
+	} {
+		set := r.set()
+		slices.Sort(set)
+		for name, got := range map[string][]int{"Set": set, "Sorted": r.sorted(), "Ordered": r.order()} {
+			if !slices.Equal(got, r.want) {
+				t.Errorf("%d. %s %s: want %v, got %v", i+1, name, r.name, r.want, got)
+			}
+		}
+	}
+}
+
+func TestSubset(t *testing.T) {
+	if !New(1, 2).IsSubset(New(1, 2, 3)) || New(1, 4).IsSubset(New(1, 2, 3)) || !New(1, 2).Equal(New(2, 1)) {
+		t.Error("Set subset error")
+	}
+	if !NewOrdered(1, 2).IsSubset(NewOrdered(3, 2, 1)) || !NewOrdered(1, 2).Equal(NewOrdered(2, 1)) {
+		t.Error("Ordered subset error")
+	}
+	s := NewSorted(cmp.Compare[int], 1, 2)
+	if !s.IsSubset(NewSorted(cmp.Compare[int], 1, 2, 3)) || s.IsSuperset(NewSorted(cmp.Compare[int], 1, 3)) {
+		t.Error("Sorted subset error")
+	}
+	var z Sorted[int]
+	if err := z.UnmarshalJSON([]byte("[1]")); !errors.Is(err, ErrorComparerUndefined) {
+		t.Errorf("want %v, got %v", ErrorComparerUndefined, err)
+	}
+}
+
+func TestOrderedRemove(t *testing.T) {
+	s := NewOrdered(1, 2, 3, 4)
+	s.Remove(1, 4, 9)
+	s.Add(1)
+	if got := s.Values(); !slices.Equal(got, []int{2, 3, 1}) {
+		t.Errorf("want [2 3 1], got %v", got)
+	}
+	s.Remove(2, 3, 1)
+	if s.Len() != 0 || s.head != nil || s.tail != nil {
+		t.Error("want empty set")
+	}
+}
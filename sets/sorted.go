@@ -0,0 +1,274 @@
+package sets
+
+import (
+	"encoding/json"
+	"errors"
+	"iter"
+	"slices"
+
+	"github.com/keepitlight/golang"
+)
+
+var (
+	ErrorComparerUndefined = errors.New("sets: comparer undefined")
+)
+
+// Sorted is the set of elements sorted by the comparator, the elements compared equal are the same
+// element. Sorted is not safe for concurrent use.
+//
+// 使用比较函数排序的元素集合，比较结果相等的元素视为同一元素。Sorted 不能安全地并发使用
+type Sorted[T any] struct {
+	compare func(a, b T) int
+	items   []T
+}
+
+// NewSorted creates a sorted set of the items with the comparator, e.g. cmp.Compare or the Comparer
+// of golang.Range.
+//
+// 使用比较函数 compare 与 items 创建有序集合，例如 cmp.Compare 或 golang.Range 的 Comparer
+func NewSorted[T any](compare func(a, b T) int, items ...T) *Sorted[T] {
+	s := &Sorted[T]{compare: compare, items: slices.Clone(items)}
+	slices.SortStableFunc(s.items, compare)
+	s.items = slices.CompactFunc(s.items, func(a, b T) bool { return compare(a, b) == 0 })
+	return s
+}
+
+// CollectSorted creates a sorted set of the elements of the sequence with the comparator.
+//
+// 使用比较函数与序列的元素创建有序集合
+func CollectSorted[T any](compare func(a, b T) int, seq iter.Seq[T]) *Sorted[T] {
+	s := NewSorted(compare)
+	for v := range seq {
+		s.Add(v)
+	}
+	return s
+}
+
+func (s *Sorted[T]) search(v T) (int, bool) {
+	return slices.BinarySearchFunc(s.items, v, s.compare)
+}
+
+// Comparer returns the comparator of the set.
+//
+// 返回集合的比较函数
+func (s *Sorted[T]) Comparer() func(a, b T) int {
+	return s.compare
+}
+
+// Add inserts the items which are not in the set.
+//
+// 插入不在集合中的元素
+func (s *Sorted[T]) Add(items ...T) *Sorted[T] {
+	for _, v := range items {
+		if i, ok := s.search(v); !ok {
+			s.items = slices.Insert(s.items, i, v)
+		}
+	}
+	return s
+}
+
+// Remove removes the items.
+//
+// 删除元素
+func (s *Sorted[T]) Remove(items ...T) *Sorted[T] {
+	for _, v := range items {
+		if i, ok := s.search(v); ok {
+			s.items = slices.Delete(s.items, i, i+1)
+		}
+	}
+	return s
+}
+
+// Contains reports whether v is in the set.
+//
+// 检查 v 是否在集合中
+func (s *Sorted[T]) Contains(v T) bool {
+	_, ok := s.search(v)
+	return ok
+}
+
+// Len returns the number of elements.
+//
+// 返回元素个数
+func (s *Sorted[T]) Len() int {
+	return len(s.items)
+}
+
+// Clear removes all elements.
+//
+// 清空集合
+func (s *Sorted[T]) Clear() {
+	s.items = nil
+}
+
+// Clone returns a copy of the set.
+//
+// 返回集合的副本
+func (s *Sorted[T]) Clone() *Sorted[T] {
+	return &Sorted[T]{compare: s.compare, items: slices.Clone(s.items)}
+}
+
+// Min returns the smallest element, false if the set is empty.
+//
+// 返回最小的元素，集合为空时返回 false
+func (s *Sorted[T]) Min() (v T, ok bool) {
+	if len(s.items) == 0 {
+		return
+	}
+	return s.items[0], true
+}
+
+// Max returns the largest element, false if the set is empty.
+//
+// 返回最大的元素，集合为空时返回 false
+func (s *Sorted[T]) Max() (v T, ok bool) {
+	if len(s.items) == 0 {
+		return
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// All returns the sequence of the elements in ascending order.
+//
+// 按升序返回元素序列
+func (s *Sorted[T]) All() iter.Seq[T] {
+	return slices.Values(s.items)
+}
+
+// Between returns the sequence of the elements within the bounds of the range in ascending order.
+//
+// 按升序返回在范围 r 上下限之内的元素序列
+func (s *Sorted[T]) Between(r golang.Range[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		lower, upper := r.Bounds()
+		i, _ := s.search(lower)
+		for ; i < len(s.items) && s.compare(s.items[i], upper) <= 0; i++ {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the elements in ascending order.
+//
+// 按升序返回元素切片
+func (s *Sorted[T]) Values() []T {
+	return slices.Clone(s.items)
+}
+
+// merge merges the sorted elements of s and o, keeps the elements selected by keep, which reports
+// whether the element is in s and o.
+func (s *Sorted[T]) merge(o *Sorted[T], keep func(inS, inO bool) bool) *Sorted[T] {
+	c := &Sorted[T]{compare: s.compare}
+	i, j := 0, 0
+	for i < len(s.items) || j < len(o.items) {
+		var (
+			v      T
+			inS    bool
+			inO    bool
+			result int
+		)
+		switch {
+		case i >= len(s.items):
+			result = 1
+		case j >= len(o.items):
+			result = -1
+		default:
+			result = s.compare(s.items[i], o.items[j])
+		}
+		switch {
+		case result < 0:
+			v, inS = s.items[i], true
+			i++
+		case result > 0:
+			v, inO = o.items[j], true
+			j++
+		default:
+			v, inS, inO = s.items[i], true, true
+			i++
+			j++
+		}
+		if keep(inS, inO) {
+			c.items = append(c.items, v)
+		}
+	}
+	return c
+}
+
+// Union returns a new set of the elements in s or o, with the comparator of s.
+//
+// 并集，返回在 s 或 o 中的元素组成的新集合，使用 s 的比较函数
+func (s *Sorted[T]) Union(o *Sorted[T]) *Sorted[T] {
+	return s.merge(o, func(inS, inO bool) bool { return true })
+}
+
+// Intersect returns a new set of the elements in both s and o, with the comparator of s.
+//
+// 交集，返回同时在 s 与 o 中的元素组成的新集合，使用 s 的比较函数
+func (s *Sorted[T]) Intersect(o *Sorted[T]) *Sorted[T] {
+	return s.merge(o, func(inS, inO bool) bool { return inS && inO })
+}
+
+// Difference returns a new set of the elements in s but not in o, with the comparator of s.
+//
+// 差集，返回在 s 中但不在 o 中的元素组成的新集合，使用 s 的比较函数
+func (s *Sorted[T]) Difference(o *Sorted[T]) *Sorted[T] {
+	return s.merge(o, func(inS, inO bool) bool { return inS && !inO })
+}
+
+// SymmetricDifference returns a new set of the elements in either s or o but not both, with the
+// comparator of s.
+//
+// 对称差集，返回仅在 s 或仅在 o 中的元素组成的新集合，使用 s 的比较函数
+func (s *Sorted[T]) SymmetricDifference(o *Sorted[T]) *Sorted[T] {
+	return s.merge(o, func(inS, inO bool) bool { return inS != inO })
+}
+
+// IsSubset reports whether every element of s is in o.
+//
+// 检查 s 是否为 o 的子集
+func (s *Sorted[T]) IsSubset(o *Sorted[T]) bool {
+	return s.Len() <= o.Len() && s.Difference(o).Len() == 0
+}
+
+// IsSuperset reports whether every element of o is in s.
+//
+// 检查 s 是否为 o 的超集
+func (s *Sorted[T]) IsSuperset(o *Sorted[T]) bool {
+	return o.IsSubset(s)
+}
+
+// Equal reports whether s and o contain the same elements.
+//
+// 检查 s 与 o 是否包含相同的元素
+func (s *Sorted[T]) Equal(o *Sorted[T]) bool {
+	return s.Len() == o.Len() && s.IsSubset(o)
+}
+
+// MarshalJSON marshals the set as an array in ascending order.
+//
+// 按升序将集合序列化为数组
+func (s *Sorted[T]) MarshalJSON() ([]byte, error) {
+	if s.items == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.items)
+}
+
+// UnmarshalJSON unmarshals the array into the set, the set must be created by NewSorted to have a
+// comparator, otherwise returns ErrorComparerUndefined.
+//
+// 将数组反序列化为集合，集合必须由 NewSorted 创建以具有比较函数，否则返回 ErrorComparerUndefined
+func (s *Sorted[T]) UnmarshalJSON(b []byte) error {
+	if s.compare == nil {
+		return ErrorComparerUndefined
+	}
+	var items []T
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	s.Clear()
+	s.Add(items...)
+	return nil
+}
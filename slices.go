@@ -4,32 +4,97 @@ import (
 	"strings"
 )
 
-// Unique returns a slice of elements that contains no duplicate elements, keeping the original order
+// Unique returns a slice of elements that contains no duplicate elements, keeping the original order,
+// runs in linear time.
 //
-// 返回不重复的元素切片，保持非重复切片元素的相对顺序，索引大的重复元素被扔掉。
+// 返回不重复的元素切片，保持非重复切片元素的相对顺序，索引大的重复元素被扔掉，时间复杂度为线性。
 // 注意，不同于 pie.Unique 方法与 slices.Compact 方法
 func Unique[E comparable](ss []E) (re []E) {
 	if len(ss) < 2 {
 		return ss
 	}
+	seen := make(map[E]struct{}, len(ss))
 	for _, s := range ss {
-		f := false
-		for _, v := range re {
-			if v == s {
-				f = true
-				break
-			}
-		}
-		if !f {
+		if _, f := seen[s]; !f {
+			seen[s] = struct{}{}
 			re = append(re, s)
 		}
 	}
 	return
 }
 
-// UniqueFunc returns a slice of elements that contains no duplicate elements, keeping the original order
+type uniqueOptions[E any] struct {
+	last     bool
+	priority func(index int, ele E) int
+}
+
+// UniqueOption is the option of UniqueBy which decides the kept one of the duplicate elements.
+//
+// UniqueBy 选项，决定保留重复元素中的哪一个
+type UniqueOption[E any] func(o *uniqueOptions[E])
+
+// KeepLast keeps the last one of the duplicate elements, e.g. golang.KeepLast[*User]().
+//
+// 保留重复元素中的最后一个，例如 golang.KeepLast[*User]()
+func KeepLast[E any]() UniqueOption[E] {
+	return func(o *uniqueOptions[E]) {
+		o.last = true
+	}
+}
+
+// KeepPriority keeps the one with the highest priority of the duplicate elements, the earlier one is
+// kept if the priorities are equal (the later one if KeepLast is also set), same as MapFunc.
+//
+// 保留重复元素中优先级最高的一个，优先级相同时保留较早的一个（同时设置了 KeepLast 时保留较晚的一个），与 MapFunc 相同
+func KeepPriority[E any](priority func(index int, ele E) int) UniqueOption[E] {
+	return func(o *uniqueOptions[E]) {
+		o.priority = priority
+	}
+}
+
+// UniqueBy returns a slice of elements that contains no elements with duplicate keys, runs in linear time.
+// The result keeps the order in which the keys are first seen, the first one of the duplicate elements
+// is kept by default, see KeepLast and KeepPriority.
+//
+// 返回键不重复的元素切片，时间复杂度为线性。结果按键首次出现的顺序排列，默认保留重复元素中的第一个，
+// 参见 KeepLast 与 KeepPriority
+func UniqueBy[E any, K comparable](ss []E, key func(ele E) K, opts ...UniqueOption[E]) (result []E) {
+	if len(ss) < 2 {
+		return ss
+	}
+	o := &uniqueOptions[E]{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	var (
+		positions  = make(map[K]int, len(ss)) // 键在结果中的位置
+		priorities []int
+	)
+	for i, s := range ss {
+		p := 0
+		if o.priority != nil {
+			p = o.priority(i, s)
+		}
+		k := key(s)
+		j, f := positions[k]
+		switch {
+		case !f:
+			positions[k] = len(result)
+			result = append(result, s)
+			priorities = append(priorities, p)
+		case p > priorities[j] || o.last && p == priorities[j]:
+			result[j] = s
+			priorities[j] = p
+		}
+	}
+	return
+}
+
+// UniqueFunc returns a slice of elements that contains no duplicate elements, keeping the original order,
+// runs in quadratic time, use UniqueBy if the elements can be identified by a comparable key.
 //
-// 根据比较方法的结果返回不重复的元素切片，不改变切片顺序，检索到重复的元素时，索引大的重复元素被扔掉
+// 根据比较方法的结果返回不重复的元素切片，不改变切片顺序，检索到重复的元素时，索引大的重复元素被扔掉，
+// 时间复杂度为平方级，元素可由可比较的键标识时请使用 UniqueBy
 func UniqueFunc[E any](ss []E, equal func(a, b E) bool) (result []E) {
 	l := len(ss)
 	if l < 2 {
@@ -136,6 +201,7 @@ func Cast[E, R any](ss []E, cast func(index int, ele E) (v R, omitted bool)) (re
 
 // MapFunc to convert slice to map, use f to convert each element to a new key and value, existing
 // key-value pairs be overwritten if priority is greater, the same priority, the existing key is higher.
+// The err is always nil, see TryMapFunc and MapPolicy for the error-returning variants.
 //
 // 切片转换为映射，使用函数 f 对每个元素进行转换，返回转换结果。
 // 使用 cast 函数的返回值 priority 决定是否覆盖已存在的键值对，priority 越大，优先级越高，优先级高的覆盖低的，相同时已存在的优先。
@@ -158,6 +224,7 @@ func MapFunc[E any, K comparable, V any](ss []E, cast func(index int, ele E) (ke
 }
 
 // Map convert slice to map, use f to convert each element to a new key and value, existing key-value pairs will be overwritten.
+// The err is always nil, see TryMap and MapPolicy for the error-returning variants.
 //
 // 以覆盖模式将切片转换为映射，使用函数 cast 对每个元素进行转换，以返回值 key 和 value 作为新的键值对。已存在的键值对将被覆盖。
 func Map[E any, K comparable, V any](ss []E, f func(index int, ele E) (key K, value V)) (result map[K]V, err error) {
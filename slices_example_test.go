@@ -1,7 +1,10 @@
 package golang_test
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+
 	"github.com/keepitlight/golang"
 )
 
@@ -170,3 +173,121 @@ func ExampleApply() {
 	// Output:
 	// [2 4 6 8 10]
 }
+
+func ExampleUniqueBy() {
+	type user struct {
+		Name    string
+		Version int
+	}
+	users := []user{{"alice", 1}, {"bob", 1}, {"alice", 3}, {"bob", 2}, {"alice", 2}}
+	name := func(u user) string { return u.Name }
+	fmt.Println(golang.UniqueBy(users, name))
+	fmt.Println(golang.UniqueBy(users, name, golang.KeepLast[user]()))
+	fmt.Println(golang.UniqueBy(users, name, golang.KeepPriority(func(i int, u user) int { return u.Version })))
+	// Output:
+	// [{alice 1} {bob 1}]
+	// [{alice 2} {bob 2}]
+	// [{alice 3} {bob 2}]
+}
+
+func ExampleParallelCast() {
+	ss := []string{"1", "2", "x", "4"}
+	vs, err := golang.ParallelCast(context.Background(), ss, 2, func(ctx context.Context, i int, s string) (int, bool, error) {
+		v, err := strconv.Atoi(s)
+		// 跳过无法解析的元素
+		return v, err != nil, nil
+	})
+	fmt.Println(vs, err)
+	// Output:
+	// [1 2 4] <nil>
+}
+
+func ExamplePartition() {
+	p := golang.Partition([]int{1, 2, 3, 4, 5}, func(i, e int) bool { return e%2 == 0 })
+	fmt.Println(p.A, p.B)
+	// Output:
+	// [2 4] [1 3 5]
+}
+
+func ExampleGroupBy() {
+	for _, g := range golang.GroupBy([]string{"go", "rust", "java", "ruby", "js"}, func(i int, s string) byte { return s[0] }) {
+		fmt.Println(string(g.A), g.B)
+	}
+	// Output:
+	// g [go]
+	// r [rust ruby]
+	// j [java js]
+}
+
+func ExampleDiff() {
+	old := []string{"a", "b", "c", "d"}
+	script := golang.Diff(old, []string{"a", "c", "d", "e"}, func(x, y string) bool { return x == y })
+	for _, e := range script {
+		fmt.Println(e.Kind, e.Old, e.New, e.Value)
+	}
+	added, removed := golang.Changes(script)
+	fmt.Println(added, removed)
+	fmt.Println(golang.ApplyEdits(old, script))
+	// Output:
+	// equal 0 0 a
+	// delete 1 -1 b
+	// equal 2 1 c
+	// equal 3 2 d
+	// insert -1 3 e
+	// [e] [b]
+	// [a c d e] <nil>
+}
+
+func ExampleSortedSlice() {
+	type event struct {
+		Name     string
+		Priority int
+	}
+	byPriority := golang.CompareBy(func(e event) int { return e.Priority })
+	s := golang.NewSortedSlice(byPriority.Desc().Then(golang.CompareBy(func(e event) string { return e.Name })),
+		event{"b", 1}, event{"a", 3}, event{"c", 1})
+	s.Insert(event{"d", 2})
+	fmt.Println(s.Values())
+	fmt.Println(s.Between(event{Priority: 2}, event{"z", 1}))
+	lower, upper := s.Range().Bounds()
+	fmt.Println(lower, upper)
+	// Output:
+	// [{a 3} {d 2} {b 1} {c 1}]
+	// [{d 2} {b 1} {c 1}]
+	// {a 3} {c 1}
+}
+
+func ExampleMapPolicy() {
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := []user{{1, "alice"}, {2, "bob"}, {1, "carol"}}
+	_, err := golang.MapPolicy(users, func(i int, u user) (int, string, int, error) {
+		return u.ID, u.Name, 0, nil
+	}, golang.DuplicateError)
+	fmt.Println(err)
+	m, _ := golang.MapPolicy(users, func(i int, u user) (int, string, int, error) {
+		return u.ID, u.Name, 0, nil
+	}, golang.DuplicateFirst)
+	fmt.Println(m)
+	// Output:
+	// golang: duplicate key 1 at index 0 and 2
+	// map[1:alice 2:bob]
+}
+
+func ExampleTopK() {
+	type request struct {
+		Path    string
+		Latency int
+	}
+	requests := []request{{"/a", 120}, {"/b", 30}, {"/c", 450}, {"/d", 80}, {"/e", 300}}
+	byLatency := golang.CompareBy(func(r request) int { return r.Latency })
+	fmt.Println(golang.TopK(requests, 2, byLatency))
+	fmt.Println(golang.BottomK(requests, 2, byLatency))
+	fmt.Println(golang.MaxBy(requests, byLatency))
+	// Output:
+	// [{/c 450} {/e 300}]
+	// [{/b 30} {/d 80}]
+	// {/c 450} 2
+}
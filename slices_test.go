@@ -1,6 +1,7 @@
 package golang
 
 import (
+	"fmt"
 	"slices"
 	"testing"
 )
@@ -21,6 +22,87 @@ func TestUnique(t *testing.T) {
 	}
 }
 
+func TestUniqueBy(t *testing.T) {
+	type item struct {
+		id, version int
+	}
+	input := []item{{1, 2}, {2, 1}, {1, 3}, {3, 1}, {2, 5}, {1, 1}}
+	id := func(e item) int { return e.id }
+	version := func(i int, e item) int { return e.version }
+	for i, r := range []struct {
+		opts []UniqueOption[item]
+		want []item
+	}{
+		{nil, []item{{1, 2}, {2, 1}, {3, 1}}},
+		{[]UniqueOption[item]{KeepLast[item]()}, []item{{1, 1}, {2, 5}, {3, 1}}},
+		{[]UniqueOption[item]{KeepPriority(version)}, []item{{1, 3}, {2, 5}, {3, 1}}},
+	} {
+		if got := UniqueBy(input, id, r.opts...); !slices.Equal(got, r.want) {
+			t.Errorf("%d. UniqueBy(%v): want %v, got %v", i+1, input, r.want, got)
+		}
+	}
+}
+
+// uniqueQuadratic is the previous implementation of Unique, kept for the benchmarks.
+func uniqueQuadratic[E comparable](ss []E) (re []E) {
+	for _, s := range ss {
+		f := false
+		for _, v := range re {
+			if v == s {
+				f = true
+				break
+			}
+		}
+		if !f {
+			re = append(re, s)
+		}
+	}
+	return
+}
+
+func benchmarkIDs(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		// 约一半重复
+		ids[i] = (i * 7919) % (n / 2)
+	}
+	return ids
+}
+
+func BenchmarkUnique(b *testing.B) {
+	for _, n := range []int{100, 10000, 100000} {
+		ids := benchmarkIDs(n)
+		b.Run(fmt.Sprintf("hash/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Unique(ids)
+			}
+		})
+		if n > 10000 {
+			// 平方级实现过慢，跳过
+			continue
+		}
+		b.Run(fmt.Sprintf("quadratic/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				uniqueQuadratic(ids)
+			}
+		})
+	}
+}
+
+func BenchmarkUniqueBy(b *testing.B) {
+	ids := benchmarkIDs(100000)
+	b.Run("first", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			UniqueBy(ids, func(e int) int { return e })
+		}
+	})
+	b.Run("priority", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			UniqueBy(ids, func(e int) int { return e }, KeepPriority(func(i, e int) int { return i }))
+		}
+	})
+}
+
 func TestSlices(t *testing.T) {
 	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 	for i, r := range []struct {
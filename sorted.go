@@ -0,0 +1,174 @@
+package golang
+
+import (
+	"iter"
+	"slices"
+)
+
+// SortedSlice is the slice kept sorted by the comparator, the equal elements are kept in insertion order.
+// SortedSlice is not safe for concurrent use.
+//
+// 使用比较函数保持有序的切片，相等的元素按插入顺序排列。SortedSlice 不能安全地并发使用
+type SortedSlice[T any] struct {
+	compare func(a, b T) int
+	items   []T
+}
+
+// NewSortedSlice creates the sorted slice of the items with the comparator.
+//
+// 使用比较函数与 items 创建有序切片
+func NewSortedSlice[T any](compare func(a, b T) int, items ...T) *SortedSlice[T] {
+	s := &SortedSlice[T]{compare: compare, items: slices.Clone(items)}
+	slices.SortStableFunc(s.items, compare)
+	return s
+}
+
+// Comparer returns the comparator of the sorted slice.
+//
+// 返回有序切片的比较函数
+func (s *SortedSlice[T]) Comparer() func(a, b T) int {
+	return s.compare
+}
+
+// LowerBound returns the index of the first element which is not less than v, Len if none.
+//
+// 返回第一个不小于 v 的元素的索引，没有则返回 Len
+func (s *SortedSlice[T]) LowerBound(v T) int {
+	i, _ := slices.BinarySearchFunc(s.items, v, s.compare)
+	return i
+}
+
+// UpperBound returns the index of the first element which is greater than v, Len if none.
+//
+// 返回第一个大于 v 的元素的索引，没有则返回 Len
+func (s *SortedSlice[T]) UpperBound(v T) int {
+	lo, hi := s.LowerBound(v), len(s.items)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if s.compare(s.items[m], v) <= 0 {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	return lo
+}
+
+// Find returns the index of the first element which equals v.
+//
+// 返回第一个与 v 相等的元素的索引
+func (s *SortedSlice[T]) Find(v T) (index int, found bool) {
+	return slices.BinarySearchFunc(s.items, v, s.compare)
+}
+
+// Contains reports whether there is an element which equals v.
+//
+// 检查是否存在与 v 相等的元素
+func (s *SortedSlice[T]) Contains(v T) bool {
+	_, found := s.Find(v)
+	return found
+}
+
+// Insert inserts the items, each after the elements which equal it.
+//
+// 插入元素，每个元素插入在与其相等的元素之后
+func (s *SortedSlice[T]) Insert(items ...T) *SortedSlice[T] {
+	for _, v := range items {
+		s.items = slices.Insert(s.items, s.UpperBound(v), v)
+	}
+	return s
+}
+
+// Remove removes the first element which equals v, returns false if none.
+//
+// 删除第一个与 v 相等的元素，没有则返回 false
+func (s *SortedSlice[T]) Remove(v T) bool {
+	i, found := s.Find(v)
+	if found {
+		s.items = slices.Delete(s.items, i, i+1)
+	}
+	return found
+}
+
+// RemoveAll removes all elements which equal v, returns the number of removed elements.
+//
+// 删除所有与 v 相等的元素，返回删除的元素个数
+func (s *SortedSlice[T]) RemoveAll(v T) int {
+	i, j := s.LowerBound(v), s.UpperBound(v)
+	s.items = slices.Delete(s.items, i, j)
+	return j - i
+}
+
+// Between returns the elements in the closed interval [lower, upper], the result shares the memory of
+// the sorted slice and is only valid until the next modification.
+//
+// 返回闭区间 [lower, upper] 内的元素，结果与有序切片共用内存，仅在下一次修改前有效
+func (s *SortedSlice[T]) Between(lower, upper T) []T {
+	i, j := s.LowerBound(lower), s.UpperBound(upper)
+	if i >= j {
+		return nil
+	}
+	return s.items[i:j:j]
+}
+
+// Within returns the elements within the bounds of the range, see Between.
+//
+// 返回在范围 r 上下限之内的元素，参见 Between
+func (s *SortedSlice[T]) Within(r Range[T]) []T {
+	lower, upper := r.Bounds()
+	return s.Between(lower, upper)
+}
+
+// Range returns the range view of the smallest and largest elements, nil if the slice is empty.
+//
+// 返回最小与最大元素构成的范围视图，切片为空时返回 nil
+func (s *SortedSlice[T]) Range() Range[T] {
+	if len(s.items) == 0 {
+		return nil
+	}
+	return &sortedRange[T]{s: s}
+}
+
+// Len returns the number of elements.
+//
+// 返回元素个数
+func (s *SortedSlice[T]) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th element.
+//
+// 返回第 i 个元素
+func (s *SortedSlice[T]) At(i int) T {
+	return s.items[i]
+}
+
+// All returns the sequence of the index and element in ascending order.
+//
+// 按升序返回索引与元素的序列
+func (s *SortedSlice[T]) All() iter.Seq2[int, T] {
+	return slices.All(s.items)
+}
+
+// Values returns a copy of the elements in ascending order.
+//
+// 按升序返回元素的副本
+func (s *SortedSlice[T]) Values() []T {
+	return slices.Clone(s.items)
+}
+
+// sortedRange is the range view of a sorted slice, the bounds follow the changes of the slice.
+type sortedRange[T any] struct {
+	s *SortedSlice[T]
+}
+
+func (r *sortedRange[T]) Bounds() (lower, upper T) {
+	if n := len(r.s.items); n > 0 {
+		return r.s.items[0], r.s.items[n-1]
+	}
+	return
+}
+
+func (r *sortedRange[T]) Comparer() func(a, b T) int {
+	return r.s.compare
+}
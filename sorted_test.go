@@ -0,0 +1,83 @@
+package golang
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortedSlice(t *testing.T) {
+	s := NewSortedSlice(cmp.Compare[int], 5, 1, 3, 3, 9)
+	s.Insert(4, 3, 10, 0)
+	if want := []int{0, 1, 3, 3, 3, 4, 5, 9, 10}; !slices.Equal(s.Values(), want) {
+		t.Fatalf("want %v, got %v", want, s.Values())
+	}
+	for i, r := range []struct {
+		got, want int
+	}{
+		{s.LowerBound(3), 2},
+		{s.UpperBound(3), 5},
+		{s.LowerBound(6), 7},
+		{s.UpperBound(10), 9},
+		{s.LowerBound(-1), 0},
+	} {
+		if r.got != r.want {
+			t.Errorf("%d. want %d, got %d", i+1, r.want, r.got)
+		}
+	}
+	if i, ok := s.Find(4); !ok || i != 5 {
+		t.Errorf("Find(4): want 5, got %d, %v", i, ok)
+	}
+	if _, ok := s.Find(7); ok {
+		t.Error("Find(7): want not found")
+	}
+	if got := s.Between(2, 5); !slices.Equal(got, []int{3, 3, 3, 4, 5}) {
+		t.Errorf("Between(2, 5): got %v", got)
+	}
+	if got := s.Between(6, 8); got != nil {
+		t.Errorf("Between(6, 8): want nil, got %v", got)
+	}
+	if n := s.RemoveAll(3); n != 3 || !s.Remove(0) || s.Remove(0) {
+		t.Errorf("Remove: got %d, %v", n, s.Values())
+	}
+	r := s.Range()
+	if lower, upper := r.Bounds(); lower != 1 || upper != 10 {
+		t.Errorf("Range: want [1, 10], got [%d, %d]", lower, upper)
+	}
+	if got := s.Within(r); !slices.Equal(got, s.Values()) {
+		t.Errorf("Within: got %v", got)
+	}
+	if NewSortedSlice(cmp.Compare[int]).Range() != nil {
+		t.Error("Range of empty slice: want nil")
+	}
+}
+
+func TestComparator(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+	}
+	users := []user{{"bob", 30}, {"alice", 25}, {"bob", 40}, {"alice", 35}}
+	slices.SortFunc(users, CompareBy(func(u user) string { return u.name }).Then(CompareBy(func(u user) int { return u.age }).Desc()))
+	if want := []user{{"alice", 35}, {"alice", 25}, {"bob", 40}, {"bob", 30}}; !slices.Equal(users, want) {
+		t.Errorf("want %v, got %v", want, users)
+	}
+
+	one, two := 1, 2
+	ps := []*int{&two, nil, &one}
+	slices.SortFunc(ps, NilFirst(cmp.Compare[int]))
+	if ps[0] != nil || *ps[1] != 1 || *ps[2] != 2 {
+		t.Errorf("NilFirst: got %v", ps)
+	}
+	slices.SortFunc(ps, NilLast(cmp.Compare[int]).Desc())
+	if ps[0] != nil || *ps[1] != 2 || *ps[2] != 1 {
+		t.Errorf("NilLast desc: got %v", ps)
+	}
+
+	now := time.Now()
+	later := now.Add(time.Second)
+	if TimeCompare(&now, &later) != -1 || TimeCompare(nil, &now) != 1 || TimeCompare(&now, nil) != -1 || TimeCompare(nil, nil) != 0 {
+		t.Error("TimeCompare error")
+	}
+}
@@ -63,23 +63,11 @@ func AddDays(t time.Time, days float64) time.Time {
 	return time.Date(y, m, d+int(D), h+hours, x+minutes, s+seconds, n+nano, t.Location())
 }
 
+// TimeCompare compares the times, nil sorts after the others.
+//
+// 比较时间，nil 排在最后
 func TimeCompare(a, b *time.Time) int {
-	if a == nil {
-		if b == nil {
-			return 0
-		}
-		return 1
-	}
-	if b == nil {
-		return -1
-	}
-	if a.Equal(*b) {
-		return 0
-	}
-	if a.Before(*b) {
-		return -1
-	} else if a.After(*b) {
-		return 1
-	}
-	return 0
+	return timeCompare(a, b)
 }
+
+var timeCompare = NilLast(time.Time.Compare)